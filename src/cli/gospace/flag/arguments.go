@@ -3,12 +3,22 @@ package flag
 // commandline arguments registry.
 // contains the parsed values from the commandline
 type Arguments struct {
+	Command   *Command
 	Blank     bool
 	NoRun     bool
+	Exec      bool
+	Wait      bool
+	Man       bool
+	Markdown  bool
+	Pick      bool
+	Triggered bool
+	Format    string
 	GoSDK     string
 	Shell     string
 	ShellArgv []string
 	Path      []string
+	Operands  []string
+	Include   []string
 }
 
 // convenient wrapper to append a value to the shell argument slice
@@ -21,10 +31,32 @@ func (a *Arguments) AppendPath(value string) {
 	a.Path = append(a.Path, value)
 }
 
+// convenient wrapper to append a value to the command operand slice
+func (a *Arguments) AppendOperand(value string) {
+	a.Operands = append(a.Operands, value)
+}
+
+// check if neither paths nor operands have been provided so far
+func (a *Arguments) IsEmpty() bool {
+	return 0 == len(a.Path) && 0 == len(a.Operands)
+}
+
+// clear the values collected from the commandline and select the
+// command. values bound to parameters are reset by the registry.
+func (a *Arguments) Reset(command *Command) {
+	a.Command = command
+	a.Triggered = false
+	a.ShellArgv = []string{}
+	a.Path = []string{}
+	a.Operands = []string{}
+}
+
 // argument registry instance factory
 func NewArguments() *Arguments {
 	shellParams := []string{}
 	includePath := []string{}
+	operands := []string{}
+	include := []string{}
 
-	return &Arguments{false, false, "", "", shellParams, includePath}
+	return &Arguments{nil, false, false, false, false, false, false, false, false, "", "", "", shellParams, includePath, operands, include}
 }
@@ -0,0 +1,175 @@
+package flag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// node of the command tree. the root node is the default command
+// which is used if the commandline does not name a sub-command.
+type Command struct {
+	// the word selecting the command on the commandline
+	Name string
+	// operand description for the usage, e.g. [PATH]...
+	Synopsis string
+	// single line summary
+	Description string
+	// resolve the operands as workspace paths instead of passing
+	// them on verbatim
+	Paths bool
+	// invoked with the parsed arguments
+	Callback *Callback
+	// options accepted by the command
+	Parameters []*Parameter
+	// sub-commands
+	Commands []*Command
+	// operands offered by the completion
+	Choices []string
+	parent  *Command
+}
+
+// register sub-commands
+func (c *Command) Add(commands ...*Command) (self *Command) {
+	self = c
+
+	for _, command := range commands {
+		command.parent = c
+		c.Commands = append(c.Commands, command)
+	}
+
+	return
+}
+
+// complete the operands with the fixed values
+func (c *Command) WithChoices(values ...string) (self *Command) {
+	self = c
+	c.Choices = values
+
+	return
+}
+
+// the names of the direct sub-commands
+func (c *Command) Names() []string {
+	names := []string{}
+
+	for _, command := range c.Commands {
+		names = append(names, command.Name)
+	}
+
+	return names
+}
+
+// find the direct sub-command by name. the result is nil if no such
+// command exists.
+func (c *Command) Find(name string) *Command {
+	for _, command := range c.Commands {
+		if name == command.Name {
+			return command
+		}
+	}
+
+	return nil
+}
+
+// find the command by its path of names, e.g. [sdk list]
+func (c *Command) Lookup(names []string) *Command {
+	current := c
+
+	for _, name := range names {
+		if current = current.Find(name); nil == current {
+			return nil
+		}
+	}
+
+	return current
+}
+
+// the root node of the command tree
+func (c *Command) Root() *Command {
+	if nil == c.parent {
+		return c
+	}
+
+	return c.parent.Root()
+}
+
+// the names of the command and its ancestors, separated by spaces.
+// the root command has an empty path.
+func (c *Command) Path() string {
+	if nil == c.parent {
+		return c.Name
+	}
+
+	return strings.TrimSpace(c.parent.Path() + " " + c.Name)
+}
+
+// the commandline of the command, e.g. gospace sdk [COMMAND]
+func (c *Command) Usage(application string) string {
+	line := strings.TrimSpace(application + " " + c.Path())
+
+	if 0 < len(c.Synopsis) {
+		line += " " + c.Synopsis
+	}
+
+	return line
+}
+
+// write the usage line of the command, its options and sub-commands
+// to the writer. the global parameters are listed after the options
+// of the command.
+func (c *Command) WriteUsage(out io.Writer, application string, globals []*Parameter) {
+	prefix := "usage: "
+	commands := []*Command{c}
+
+	if nil == c.parent {
+		commands = append(commands, c.Commands...)
+	}
+
+	for _, command := range commands {
+		io.WriteString(out, prefix+command.Usage(application)+"\n")
+		prefix = "       "
+	}
+
+	io.WriteString(out, c.Description)
+	io.WriteString(out, "\n\n")
+
+	io.WriteString(out, "arguments:\n")
+
+	for _, param := range c.Parameters {
+		io.WriteString(out, param.Usage())
+	}
+
+	for _, param := range globals {
+		if false == c.accepts(param) {
+			io.WriteString(out, param.Usage())
+		}
+	}
+
+	if 0 < len(c.Commands) {
+		io.WriteString(out, "\ncommands:\n")
+
+		for _, command := range c.Commands {
+			io.WriteString(out, fmt.Sprintf("\t%-21s %s\n", command.Name, command.Description))
+		}
+	}
+}
+
+func (c *Command) String() string {
+	return "Command(" + c.Path() + ")"
+}
+
+func (c *Command) accepts(param *Parameter) bool {
+	for _, known := range c.Parameters {
+		if known == param {
+			return true
+		}
+	}
+
+	return false
+}
+
+// command instance factory
+func NewCommand(name string, synopsis string, description string, paths bool, cb *Callback, params ...*Parameter) *Command {
+	return &Command{name, synopsis, description, paths, cb, params, []*Command{}, []string{}, nil}
+}
@@ -0,0 +1,641 @@
+package flag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// the value is not completed
+	COMPLETE_NONE = ""
+	// complete file names
+	COMPLETE_FILE = "file"
+	// complete directory names
+	COMPLETE_DIRECTORY = "directory"
+	// complete the names of executables in the PATH
+	COMPLETE_COMMAND = "command"
+	// complete one of the choices of the parameter or command
+	COMPLETE_CHOICE = "choice"
+	// complete the candidates of the same name and directories. the
+	// kind is used for the operands of commands resolving paths.
+	COMPLETE_WORKSPACE = "workspace"
+)
+
+// shells supported by WriteCompletion
+var COMPLETION_SHELLS = []string{"bash", "zsh", "fish"}
+
+// generator of a completion script for the command tree. dynamic
+// values are listed by running the candidates command with the
+// completion kind as operand, e.g. "gospace completion sdk".
+type completionScript struct {
+	application string
+	candidates  string
+	// name prefix of the shell functions
+	prefix   string
+	commands []*Command
+	params   []*Parameter
+	globals  []*Parameter
+}
+
+// write the completion script of the shell to the writer. the
+// options and commands are taken from the command tree, values of
+// completion kinds other than the COMPLETE_* constants are listed by
+// running "APPLICATION CANDIDATES KIND".
+func (p *Parser) WriteCompletion(out io.Writer, shell string, application string, candidates string) error {
+	var script string
+
+	generator := &completionScript{
+		application,
+		candidates,
+		functionName(application),
+		flatten(p.root),
+		p.registry.Parameters(),
+		p.registry.Globals(),
+	}
+
+	switch shell {
+	case "bash":
+		script = generator.bash()
+	case "zsh":
+		script = generator.zsh()
+	case "fish":
+		script = generator.fish()
+	default:
+		return Usagef("Unsupported completion shell '%s' (supported: %s)",
+			shell,
+			strings.Join(COMPLETION_SHELLS, ", "))
+	}
+
+	_, err := io.WriteString(out, script)
+
+	return err
+}
+
+func (s *completionScript) bash() string {
+	out := &bytes.Buffer{}
+	fn := "_" + s.prefix
+
+	fmt.Fprintf(out, "# bash completion for %s, generated by '%s %s bash'\n\n", s.application, s.application, s.candidates)
+
+	fmt.Fprintf(out, "%s_complete() {\n", fn)
+	fmt.Fprintf(out, "    local kind=\"$1\" value=\"$2\"\n")
+	fmt.Fprintf(out, "    shift 2\n\n")
+	fmt.Fprintf(out, "    case \"$kind\" in\n")
+	fmt.Fprintf(out, "        %s) ;;\n", COMPLETE_NONE+`""`)
+	fmt.Fprintf(out, "        %s) COMPREPLY+=($(compgen -f -- \"$value\")) ;;\n", COMPLETE_FILE)
+	fmt.Fprintf(out, "        %s) COMPREPLY+=($(compgen -d -- \"$value\")) ;;\n", COMPLETE_DIRECTORY)
+	fmt.Fprintf(out, "        %s) COMPREPLY+=($(compgen -c -- \"$value\")) ;;\n", COMPLETE_COMMAND)
+	fmt.Fprintf(out, "        %s) COMPREPLY+=($(compgen -W \"$*\" -- \"$value\")) ;;\n", COMPLETE_CHOICE)
+	fmt.Fprintf(out, "        %s)\n", COMPLETE_WORKSPACE)
+	fmt.Fprintf(out, "            COMPREPLY+=($(compgen -W \"$(command %s %s %s 2>/dev/null)\" -- \"$value\"))\n", s.application, s.candidates, COMPLETE_WORKSPACE)
+	fmt.Fprintf(out, "            COMPREPLY+=($(compgen -d -- \"$value\")) ;;\n")
+	fmt.Fprintf(out, "        *) COMPREPLY+=($(compgen -W \"$(command %s %s \"$kind\" 2>/dev/null)\" -- \"$value\")) ;;\n", s.application, s.candidates)
+	fmt.Fprintf(out, "    esac\n")
+	fmt.Fprintf(out, "}\n\n")
+
+	fmt.Fprintf(out, "%s() {\n", fn)
+	fmt.Fprintf(out, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(out, "    local command_path=\"\" operands=0 skip=0 attached=0 i word\n\n")
+	fmt.Fprintf(out, "    COMPREPLY=()\n\n")
+
+	// the = of attached values is a separate word in COMP_WORDS
+	fmt.Fprintf(out, "    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	fmt.Fprintf(out, "        word=\"${COMP_WORDS[i]}\"\n\n")
+	fmt.Fprintf(out, "        if [ \"=\" = \"$word\" ]; then\n")
+	fmt.Fprintf(out, "            skip=1\n")
+	fmt.Fprintf(out, "            continue\n")
+	fmt.Fprintf(out, "        elif [ 1 = \"$skip\" ]; then\n")
+	fmt.Fprintf(out, "            skip=0\n")
+	fmt.Fprintf(out, "            continue\n")
+	fmt.Fprintf(out, "        fi\n\n")
+	fmt.Fprintf(out, "        case \"$word\" in\n")
+	fmt.Fprintf(out, "            --)\n")
+	fmt.Fprintf(out, "                %s_complete %s \"$cur\"\n", fn, COMPLETE_FILE)
+	fmt.Fprintf(out, "                return 0 ;;\n")
+
+	if options := s.valueOptions(); 0 < len(options) {
+		fmt.Fprintf(out, "            %s) skip=1 ;;\n", strings.Join(options, "|"))
+	}
+
+	fmt.Fprintf(out, "            -*) ;;\n")
+	fmt.Fprintf(out, "            *)\n")
+	fmt.Fprintf(out, "                case \"$operands:$command_path:$word\" in\n")
+
+	if selections := s.selections(); 0 < len(selections) {
+		fmt.Fprintf(out, "                    %s) command_path=\"${command_path:+$command_path }$word\" ;;\n", strings.Join(selections, "|"))
+	}
+
+	fmt.Fprintf(out, "                    *) operands=1 ;;\n")
+	fmt.Fprintf(out, "                esac ;;\n")
+	fmt.Fprintf(out, "        esac\n")
+	fmt.Fprintf(out, "    done\n\n")
+
+	fmt.Fprintf(out, "    if [ \"=\" = \"$cur\" ]; then\n")
+	fmt.Fprintf(out, "        cur=\"\"\n")
+	fmt.Fprintf(out, "        attached=1\n")
+	fmt.Fprintf(out, "    elif [ \"=\" = \"$prev\" ]; then\n")
+	fmt.Fprintf(out, "        prev=\"${COMP_WORDS[COMP_CWORD-2]}\"\n")
+	fmt.Fprintf(out, "        attached=1\n")
+	fmt.Fprintf(out, "    fi\n\n")
+
+	fmt.Fprintf(out, "    case \"$attached:$prev\" in\n")
+
+	for _, param := range s.params {
+		if param.IsFlag() {
+			continue
+		}
+
+		patterns := []string{"1:--" + param.Long}
+
+		if param.RequiresValue() {
+			patterns = []string{"*:--" + param.Long}
+
+			if 0 != param.Short {
+				patterns = append(patterns, "*:-"+string(param.Short))
+			}
+		}
+
+		fmt.Fprintf(out, "        %s)\n", strings.Join(patterns, "|"))
+		fmt.Fprintf(out, "            %s\n", strings.Join(append([]string{fn + "_complete", bashKind(param.Completion), `"$cur"`}, param.Choices...), " "))
+		fmt.Fprintf(out, "            return 0 ;;\n")
+	}
+
+	fmt.Fprintf(out, "    esac\n\n")
+
+	fmt.Fprintf(out, "    case \"$cur\" in\n")
+	fmt.Fprintf(out, "        -*)\n")
+	fmt.Fprintf(out, "            case \"$command_path\" in\n")
+
+	for _, command := range s.commands {
+		fmt.Fprintf(out, "                %s) %s_complete %s \"$cur\" %s ;;\n",
+			bashQuote(command.Path()),
+			fn,
+			COMPLETE_CHOICE,
+			strings.Join(s.longOptions(command), " "))
+	}
+
+	fmt.Fprintf(out, "            esac\n")
+	fmt.Fprintf(out, "            return 0 ;;\n")
+	fmt.Fprintf(out, "    esac\n\n")
+
+	fmt.Fprintf(out, "    case \"$command_path\" in\n")
+
+	for _, command := range s.commands {
+		statements := []string{}
+
+		if 0 < len(command.Commands) {
+			statements = append(statements, fmt.Sprintf("[ 0 = \"$operands\" ] && %s_complete %s \"$cur\" %s",
+				fn,
+				COMPLETE_CHOICE,
+				strings.Join(command.Names(), " ")))
+		}
+
+		if 0 < len(command.Choices) {
+			statements = append(statements, fmt.Sprintf("%s_complete %s \"$cur\" %s",
+				fn,
+				COMPLETE_CHOICE,
+				strings.Join(command.Choices, " ")))
+		}
+
+		if command.Paths {
+			statements = append(statements, fmt.Sprintf("%s_complete %s \"$cur\"", fn, COMPLETE_WORKSPACE))
+		}
+
+		if 0 == len(statements) {
+			continue
+		}
+
+		fmt.Fprintf(out, "        %s)\n", bashQuote(command.Path()))
+
+		for _, statement := range statements {
+			fmt.Fprintf(out, "            %s\n", statement)
+		}
+
+		fmt.Fprintf(out, "            ;;\n")
+	}
+
+	fmt.Fprintf(out, "    esac\n\n")
+	fmt.Fprintf(out, "    return 0\n")
+	fmt.Fprintf(out, "}\n\n")
+	fmt.Fprintf(out, "complete -F %s %s\n", fn, s.application)
+
+	return out.String()
+}
+
+func (s *completionScript) zsh() string {
+	out := &bytes.Buffer{}
+	fn := "_" + s.prefix
+
+	fmt.Fprintf(out, "#compdef %s\n", s.application)
+	fmt.Fprintf(out, "# zsh completion for %s, generated by '%s %s zsh'\n\n", s.application, s.application, s.candidates)
+
+	fmt.Fprintf(out, "%s_candidates() {\n", fn)
+	fmt.Fprintf(out, "    local -a candidates\n")
+	fmt.Fprintf(out, "    candidates=(${(f)\"$(command %s %s $1 2>/dev/null)\"})\n", s.application, s.candidates)
+	fmt.Fprintf(out, "    compadd -a candidates\n")
+	fmt.Fprintf(out, "}\n\n")
+
+	fmt.Fprintf(out, "%s_workspaces() {\n", fn)
+	fmt.Fprintf(out, "    %s_candidates %s\n", fn, COMPLETE_WORKSPACE)
+	fmt.Fprintf(out, "    _directories\n")
+	fmt.Fprintf(out, "}\n\n")
+
+	fmt.Fprintf(out, "%s() {\n", fn)
+	fmt.Fprintf(out, "    local command_path=\"\" word\n")
+	fmt.Fprintf(out, "    local -i i start=1 operands=0 skip=0\n\n")
+	fmt.Fprintf(out, "    for (( i = 2; i < CURRENT; i++ )); do\n")
+	fmt.Fprintf(out, "        word=\"${words[i]}\"\n\n")
+	fmt.Fprintf(out, "        if (( skip )); then\n")
+	fmt.Fprintf(out, "            skip=0\n")
+	fmt.Fprintf(out, "            continue\n")
+	fmt.Fprintf(out, "        fi\n\n")
+	fmt.Fprintf(out, "        case \"$word\" in\n")
+	fmt.Fprintf(out, "            (--)\n")
+	fmt.Fprintf(out, "                _files\n")
+	fmt.Fprintf(out, "                return ;;\n")
+
+	if options := s.valueOptions(); 0 < len(options) {
+		fmt.Fprintf(out, "            (%s) skip=1 ;;\n", strings.Join(options, "|"))
+	}
+
+	fmt.Fprintf(out, "            (-*) ;;\n")
+	fmt.Fprintf(out, "            (*)\n")
+	fmt.Fprintf(out, "                case \"$operands:$command_path:$word\" in\n")
+
+	if selections := s.selections(); 0 < len(selections) {
+		fmt.Fprintf(out, "                    (%s)\n", strings.Join(selections, "|"))
+		fmt.Fprintf(out, "                        command_path=\"${command_path:+$command_path }$word\"\n")
+		fmt.Fprintf(out, "                        start=$i ;;\n")
+	}
+
+	fmt.Fprintf(out, "                    (*) operands=1 ;;\n")
+	fmt.Fprintf(out, "                esac ;;\n")
+	fmt.Fprintf(out, "        esac\n")
+	fmt.Fprintf(out, "    done\n\n")
+
+	// hide the words selecting the command from _arguments
+	fmt.Fprintf(out, "    words=(\"${(@)words[$start,-1]}\")\n")
+	fmt.Fprintf(out, "    (( CURRENT -= start - 1 ))\n\n")
+
+	fmt.Fprintf(out, "    case \"$command_path\" in\n")
+
+	for _, command := range s.commands {
+		specs := []string{}
+
+		for _, param := range s.parametersOf(command) {
+			specs = append(specs, zshSpec(param, fn)...)
+		}
+
+		operands := []string{}
+
+		if 0 < len(command.Commands) {
+			operands = append(operands, fmt.Sprintf("(( operands )) || compadd - %s;", strings.Join(command.Names(), " ")))
+		}
+
+		if 0 < len(command.Choices) {
+			operands = append(operands, fmt.Sprintf("compadd - %s;", strings.Join(command.Choices, " ")))
+		}
+
+		if command.Paths {
+			operands = append(operands, fn+"_workspaces;")
+		}
+
+		if 0 < len(operands) {
+			specs = append(specs, zshQuote("*: :{"+strings.Join(operands, " ")+"}"))
+		}
+
+		fmt.Fprintf(out, "        (%s)\n", bashQuote(command.Path()))
+		fmt.Fprintf(out, "            _arguments -s -S : \\\n")
+		fmt.Fprintf(out, "                %s ;;\n", strings.Join(specs, " \\\n                "))
+	}
+
+	fmt.Fprintf(out, "    esac\n")
+	fmt.Fprintf(out, "}\n\n")
+	// autoloaded from the fpath or sourced
+	fmt.Fprintf(out, "if [ \"$funcstack[1]\" = \"%s\" ]; then\n", fn)
+	fmt.Fprintf(out, "    %s \"$@\"\n", fn)
+	fmt.Fprintf(out, "else\n")
+	fmt.Fprintf(out, "    compdef %s %s\n", fn, s.application)
+	fmt.Fprintf(out, "fi\n")
+
+	return out.String()
+}
+
+func (s *completionScript) fish() string {
+	out := &bytes.Buffer{}
+	fn := "__" + s.prefix
+
+	fmt.Fprintf(out, "# fish completion for %s, generated by '%s %s fish'\n\n", s.application, s.application, s.candidates)
+
+	// prints the number of operands and the selected command path
+	fmt.Fprintf(out, "function %s_state\n", fn)
+	fmt.Fprintf(out, "    set -l tokens (commandline -opc)\n")
+	fmt.Fprintf(out, "    set -l command_path\n")
+	fmt.Fprintf(out, "    set -l operands 0\n")
+	fmt.Fprintf(out, "    set -l skip 0\n\n")
+	fmt.Fprintf(out, "    for word in $tokens[2..-1]\n")
+	fmt.Fprintf(out, "        if test 1 = $skip\n")
+	fmt.Fprintf(out, "            set skip 0\n")
+	fmt.Fprintf(out, "            continue\n")
+	fmt.Fprintf(out, "        end\n\n")
+	fmt.Fprintf(out, "        switch $word\n")
+	fmt.Fprintf(out, "            case --\n")
+	fmt.Fprintf(out, "                echo \"1:--\"\n")
+	fmt.Fprintf(out, "                return\n")
+
+	if options := s.valueOptions(); 0 < len(options) {
+		fmt.Fprintf(out, "            case %s\n", strings.Join(options, " "))
+		fmt.Fprintf(out, "                set skip 1\n")
+	}
+
+	fmt.Fprintf(out, "            case '-*'\n")
+	fmt.Fprintf(out, "            case '*'\n")
+	fmt.Fprintf(out, "                switch \"$operands:$command_path:$word\"\n")
+
+	if selections := s.selections(); 0 < len(selections) {
+		fmt.Fprintf(out, "                    case %s\n", strings.Join(selections, " "))
+		fmt.Fprintf(out, "                        set command_path $command_path $word\n")
+	}
+
+	fmt.Fprintf(out, "                    case '*'\n")
+	fmt.Fprintf(out, "                        set operands 1\n")
+	fmt.Fprintf(out, "                end\n")
+	fmt.Fprintf(out, "        end\n")
+	fmt.Fprintf(out, "    end\n\n")
+	fmt.Fprintf(out, "    echo \"$operands:$command_path\"\n")
+	fmt.Fprintf(out, "end\n\n")
+
+	fmt.Fprintf(out, "function %s_using\n", fn)
+	fmt.Fprintf(out, "    set -l state (string split -m 1 : -- (%s_state))\n", fn)
+	fmt.Fprintf(out, "    contains -- \"$state[2]\" $argv\n")
+	fmt.Fprintf(out, "end\n\n")
+
+	fmt.Fprintf(out, "function %s_selecting\n", fn)
+	fmt.Fprintf(out, "    set -l state (string split -m 1 : -- (%s_state))\n", fn)
+	fmt.Fprintf(out, "    test 0 = \"$state[1]\"; and contains -- \"$state[2]\" $argv\n")
+	fmt.Fprintf(out, "end\n\n")
+
+	fmt.Fprintf(out, "function %s_candidates\n", fn)
+	fmt.Fprintf(out, "    command %s %s $argv[1] 2>/dev/null\n", s.application, s.candidates)
+	fmt.Fprintf(out, "end\n\n")
+
+	fmt.Fprintf(out, "function %s_workspaces\n", fn)
+	fmt.Fprintf(out, "    %s_candidates %s\n", fn, COMPLETE_WORKSPACE)
+	fmt.Fprintf(out, "    __fish_complete_directories (commandline -ct)\n")
+	fmt.Fprintf(out, "end\n\n")
+
+	for _, param := range s.params {
+		line := "complete -c " + s.application
+
+		if false == param.Global {
+			paths := []string{}
+
+			for _, command := range s.commands {
+				if command.accepts(param) {
+					paths = append(paths, fishQuote(command.Path()))
+				}
+			}
+
+			if 0 == len(paths) {
+				continue
+			}
+
+			line += " -n " + fishQuote(fn+"_using "+strings.Join(paths, " "))
+		}
+
+		if 0 != param.Short {
+			line += " -s " + string(param.Short)
+		}
+
+		line += " -l " + param.Long
+
+		if false == param.IsFlag() {
+			line += " " + fishValue(param, fn)
+		}
+
+		fmt.Fprintf(out, "%s -d %s\n", line, fishQuote(param.Description))
+	}
+
+	for _, command := range s.commands {
+		path := fishQuote(command.Path())
+
+		for _, child := range command.Commands {
+			fmt.Fprintf(out, "complete -c %s -n %s -f -a %s -d %s\n",
+				s.application,
+				fishQuote(fn+"_selecting "+path),
+				child.Name,
+				fishQuote(child.Description))
+		}
+
+		if 0 < len(command.Choices) {
+			fmt.Fprintf(out, "complete -c %s -n %s -f -a %s\n",
+				s.application,
+				fishQuote(fn+"_using "+path),
+				fishQuote(strings.Join(command.Choices, " ")))
+		}
+
+		if command.Paths {
+			fmt.Fprintf(out, "complete -c %s -n %s -f -a %s\n",
+				s.application,
+				fishQuote(fn+"_using "+path),
+				fishQuote("("+fn+"_workspaces)"))
+		}
+	}
+
+	return out.String()
+}
+
+// spellings of the options which take the next word as value
+func (s *completionScript) valueOptions() []string {
+	options := []string{}
+
+	for _, param := range s.params {
+		if param.RequiresValue() {
+			if 0 != param.Short {
+				options = append(options, "-"+string(param.Short))
+			}
+
+			options = append(options, "--"+param.Long)
+		}
+	}
+
+	return options
+}
+
+// patterns of OPERANDS:PATH:WORD selecting a sub-command
+func (s *completionScript) selections() []string {
+	selections := []string{}
+
+	for _, command := range s.commands {
+		for _, child := range command.Commands {
+			selections = append(selections, fmt.Sprintf("0:%s:%s", command.Path(), child.Name))
+		}
+	}
+
+	return selections
+}
+
+// the long options accepted by the command
+func (s *completionScript) longOptions(command *Command) []string {
+	options := []string{}
+
+	for _, param := range s.parametersOf(command) {
+		options = append(options, "--"+param.Long)
+	}
+
+	return options
+}
+
+func (s *completionScript) parametersOf(command *Command) []*Parameter {
+	params := append([]*Parameter{}, command.Parameters...)
+
+	for _, param := range s.globals {
+		if false == command.accepts(param) {
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
+
+// the commands of the tree in depth-first order
+func flatten(command *Command) []*Command {
+	commands := []*Command{command}
+
+	for _, child := range command.Commands {
+		commands = append(commands, flatten(child)...)
+	}
+
+	return commands
+}
+
+// the application name as shell function identifier
+func functionName(application string) string {
+	return strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') || '_' == r {
+			return r
+		}
+
+		return '_'
+	}, application)
+}
+
+// the bash helper completes the kinds without value as well, the
+// empty kind has to be quoted though
+func bashKind(kind string) string {
+	if COMPLETE_NONE == kind {
+		return `""`
+	}
+
+	return kind
+}
+
+// the _arguments specification of the parameter
+func zshSpec(param *Parameter, fn string) []string {
+	var exclusion string
+	var action string
+	var shortSuffix string
+	var longSuffix string
+
+	names := []string{}
+	description := "[" + zshEscape(param.Description) + "]"
+
+	if 0 != param.Short {
+		names = append(names, "-"+string(param.Short))
+	}
+
+	names = append(names, "--"+param.Long)
+
+	switch param.Kind {
+	case KIND_LIST, KIND_COUNTER:
+		exclusion = "*"
+	case KIND_TRIGGER:
+		exclusion = "(- *)"
+	default:
+		exclusion = "(" + strings.Join(names, " ") + ")"
+	}
+
+	switch param.Completion {
+	case COMPLETE_NONE:
+		action = " "
+	case COMPLETE_FILE:
+		action = "_files"
+	case COMPLETE_DIRECTORY:
+		action = "_directories"
+	case COMPLETE_COMMAND:
+		action = "_command_names -e"
+	case COMPLETE_CHOICE:
+		action = "(" + strings.Join(param.Choices, " ") + ")"
+	case COMPLETE_WORKSPACE:
+		action = fn + "_workspaces"
+	default:
+		action = fn + "_candidates " + param.Completion
+	}
+
+	switch param.Kind {
+	case KIND_STRING, KIND_LIST:
+		shortSuffix, longSuffix = "+", "="
+		description += ":" + param.ValueName + ":" + action
+	case KIND_OPTIONAL:
+		shortSuffix, longSuffix = "-", "=-"
+		description += "::" + param.ValueName + ":" + action
+	}
+
+	specs := []string{}
+
+	for _, name := range names {
+		suffix := longSuffix
+
+		if 2 == len(name) {
+			suffix = shortSuffix
+		}
+
+		specs = append(specs, zshQuote(exclusion+name+suffix+description))
+	}
+
+	return specs
+}
+
+// the complete arguments of the value of the parameter
+func fishValue(param *Parameter, fn string) string {
+	switch param.Completion {
+	case COMPLETE_NONE, COMPLETE_FILE:
+		return "-r"
+	case COMPLETE_DIRECTORY:
+		return "-x -a " + fishQuote("(__fish_complete_directories (commandline -ct))")
+	case COMPLETE_COMMAND:
+		return "-x -a " + fishQuote("(__fish_complete_command)")
+	case COMPLETE_CHOICE:
+		return "-x -a " + fishQuote(strings.Join(param.Choices, " "))
+	case COMPLETE_WORKSPACE:
+		return "-x -a " + fishQuote("("+fn+"_workspaces)")
+	default:
+		return "-x -a " + fishQuote("("+fn+"_candidates "+param.Completion+")")
+	}
+}
+
+func bashQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+func zshQuote(value string) string {
+	return bashQuote(value)
+}
+
+// brackets and colons delimit the parts of an _arguments specification
+func zshEscape(value string) string {
+	escaped := strings.Replace(value, `\`, `\\`, -1)
+	escaped = strings.Replace(escaped, "[", `\[`, -1)
+	escaped = strings.Replace(escaped, "]", `\]`, -1)
+
+	return strings.Replace(escaped, ":", `\:`, -1)
+}
+
+func fishQuote(value string) string {
+	escaped := strings.Replace(value, `\`, `\\`, -1)
+	escaped = strings.Replace(escaped, "'", `\'`, -1)
+
+	return "'" + escaped + "'"
+}
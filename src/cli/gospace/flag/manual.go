@@ -0,0 +1,253 @@
+package flag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// usage example of the reference documentation
+type Example struct {
+	// the commandline, including the application name
+	Commandline string
+	// what the commandline does
+	Description string
+}
+
+// the texts of the reference documentation which are not part of the
+// command tree
+type Manual struct {
+	Application string
+	Version     string
+	// single line summary of the application
+	Headline string
+	// free text explaining the commandline conventions
+	Description string
+	Examples    []*Example
+}
+
+// write the reference documentation of every command and option as
+// roff man page to the writer
+func (p *Parser) WriteManPage(out io.Writer, manual *Manual) error {
+	doc := &bytes.Buffer{}
+	commands := flatten(p.root)
+
+	fmt.Fprintf(doc, ".TH %s 1 \"\" \"%s %s\" \"User Commands\"\n",
+		strings.ToUpper(manual.Application),
+		manual.Application,
+		manual.Version)
+
+	fmt.Fprintf(doc, ".SH NAME\n")
+	fmt.Fprintf(doc, "%s \\- %s\n", manual.Application, roffEscape(manual.Headline))
+
+	fmt.Fprintf(doc, ".SH SYNOPSIS\n")
+
+	for i, command := range commands {
+		if 0 < i {
+			fmt.Fprintf(doc, ".br\n")
+		}
+
+		fmt.Fprintf(doc, ".B %s\n", roffEscape(strings.TrimSpace(manual.Application+" "+command.Path())))
+
+		if 0 < len(command.Synopsis) {
+			fmt.Fprintf(doc, "%s\n", roffEscape(command.Synopsis))
+		}
+	}
+
+	fmt.Fprintf(doc, ".SH DESCRIPTION\n")
+	fmt.Fprintf(doc, "%s\n", roffText(manual.Headline))
+
+	if 0 < len(manual.Description) {
+		fmt.Fprintf(doc, ".PP\n")
+		fmt.Fprintf(doc, "%s\n", roffText(manual.Description))
+	}
+
+	fmt.Fprintf(doc, ".SH COMMANDS\n")
+
+	for _, command := range commands[1:] {
+		fmt.Fprintf(doc, ".TP\n")
+		fmt.Fprintf(doc, ".B %s\n", roffEscape(command.Usage(manual.Application)))
+		fmt.Fprintf(doc, "%s\n", roffEscape(command.Description))
+
+		if options := optionNames(command.Parameters); 0 < len(options) {
+			fmt.Fprintf(doc, ".br\n")
+			fmt.Fprintf(doc, "options: %s\n", roffEscape(strings.Join(options, ", ")))
+		}
+	}
+
+	fmt.Fprintf(doc, ".SH OPTIONS\n")
+
+	for _, param := range p.registry.Parameters() {
+		fmt.Fprintf(doc, ".TP\n")
+		fmt.Fprintf(doc, ".B %s\n", roffEscape(param.Synopsis()))
+		fmt.Fprintf(doc, "%s\n", roffEscape(referenceDescription(param, p.root)))
+	}
+
+	fmt.Fprintf(doc, ".SH ENVIRONMENT\n")
+
+	for _, param := range p.registry.Parameters() {
+		if 0 < len(param.Env) {
+			fmt.Fprintf(doc, ".TP\n")
+			fmt.Fprintf(doc, ".B %s\n", param.Env)
+			fmt.Fprintf(doc, "%s\n", roffEscape(environmentDescription(param)))
+		}
+	}
+
+	if 0 < len(manual.Examples) {
+		fmt.Fprintf(doc, ".SH EXAMPLES\n")
+
+		for _, example := range manual.Examples {
+			fmt.Fprintf(doc, ".TP\n")
+			fmt.Fprintf(doc, ".B %s\n", roffEscape(example.Commandline))
+			fmt.Fprintf(doc, "%s\n", roffText(example.Description))
+		}
+	}
+
+	_, err := doc.WriteTo(out)
+
+	return err
+}
+
+// write the reference documentation of every command and option as
+// markdown page to the writer
+func (p *Parser) WriteMarkdown(out io.Writer, manual *Manual) error {
+	doc := &bytes.Buffer{}
+	commands := flatten(p.root)
+
+	fmt.Fprintf(doc, "# %s\n\n", manual.Application)
+	fmt.Fprintf(doc, "%s\n\n", manual.Headline)
+
+	fmt.Fprintf(doc, "# synopsis\n\n")
+
+	for _, command := range commands {
+		fmt.Fprintf(doc, "    %s\n", command.Usage(manual.Application))
+	}
+
+	if 0 < len(manual.Description) {
+		fmt.Fprintf(doc, "\n# description\n\n")
+		fmt.Fprintf(doc, "%s\n", manual.Description)
+	}
+
+	fmt.Fprintf(doc, "\n# commands\n")
+
+	for _, command := range commands[1:] {
+		fmt.Fprintf(doc, "\n> %s\n\n", command.Usage(manual.Application))
+		fmt.Fprintf(doc, "%s\n", command.Description)
+
+		if options := optionNames(command.Parameters); 0 < len(options) {
+			fmt.Fprintf(doc, "\noptions: _%s_\n", strings.Join(options, "_, _"))
+		}
+	}
+
+	fmt.Fprintf(doc, "\n# options\n\n")
+
+	for _, param := range p.registry.Parameters() {
+		fmt.Fprintf(doc, "    %-21s %s\n", param.Synopsis(), referenceDescription(param, p.root))
+	}
+
+	fmt.Fprintf(doc, "\n# environment\n\n")
+
+	for _, param := range p.registry.Parameters() {
+		if 0 < len(param.Env) {
+			fmt.Fprintf(doc, "* **%s**: %s\n", param.Env, environmentDescription(param))
+		}
+	}
+
+	if 0 < len(manual.Examples) {
+		fmt.Fprintf(doc, "\n# examples\n")
+
+		for _, example := range manual.Examples {
+			fmt.Fprintf(doc, "\n> %s\n\n", example.Commandline)
+			fmt.Fprintf(doc, "%s\n", example.Description)
+		}
+	}
+
+	_, err := doc.WriteTo(out)
+
+	return err
+}
+
+// the description of the option and the commands accepting it
+func referenceDescription(param *Parameter, root *Command) string {
+	description := param.Description
+
+	if KIND_LIST == param.Kind {
+		description += " (repeatable)"
+	}
+
+	if 0 < len(param.Default) && false == param.IsFlag() {
+		description += " (default: " + param.Default + ")"
+	}
+
+	if param.Global {
+		return description + "; accepted by every command"
+	}
+
+	names := []string{}
+
+	for _, command := range flatten(root) {
+		if false == command.accepts(param) {
+			continue
+		} else if command == root {
+			names = append(names, "the default command")
+		} else {
+			names = append(names, command.Path())
+		}
+	}
+
+	return description + "; accepted by " + strings.Join(names, ", ")
+}
+
+// the description of the environment variable of the option
+func environmentDescription(param *Parameter) string {
+	description := "same as --" + param.Long
+
+	switch param.Kind {
+	case KIND_BOOL:
+		description += ", enabled by 1, true, yes or on"
+	case KIND_LIST:
+		description += ", entries are separated like PATH"
+	case KIND_COUNTER:
+		description = "same as repeating --" + param.Long + ", but the value is a level instead of a count"
+	}
+
+	if 0 < len(param.ImplicitEnv) {
+		description += "; --" + param.Long + " without a value uses " + param.ImplicitEnv
+	}
+
+	return description
+}
+
+func optionNames(params []*Parameter) []string {
+	names := []string{}
+
+	for _, param := range params {
+		names = append(names, param.String())
+	}
+
+	return names
+}
+
+// escape the characters with a special meaning in roff
+func roffEscape(value string) string {
+	escaped := strings.Replace(value, `\`, `\e`, -1)
+	escaped = strings.Replace(escaped, "-", `\-`, -1)
+
+	if strings.HasPrefix(escaped, ".") || strings.HasPrefix(escaped, "'") {
+		escaped = `\&` + escaped
+	}
+
+	return escaped
+}
+
+// escape each line of the multi-line value
+func roffText(value string) string {
+	lines := strings.Split(value, "\n")
+
+	for i, line := range lines {
+		lines[i] = roffEscape(line)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -3,88 +3,250 @@ package flag
 import (
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 )
 
+const (
+	// switch without value, e.g. --blank
+	KIND_BOOL Kind = iota
+	// option with a mandatory value, e.g. --shell=PATH
+	KIND_STRING = iota
+	// option with a mandatory value which may be repeated,
+	// e.g. --include=DIR --include=DIR
+	KIND_LIST = iota
+	// switch which may be repeated, e.g. -vvv
+	KIND_COUNTER = iota
+	// option with an optional value, e.g. --go[=SDK]
+	KIND_OPTIONAL = iota
+	// switch which runs a command immediately, e.g. --help
+	KIND_TRIGGER = iota
+)
+
+// value type of a parameter
+type Kind int
+
+// target of a counter parameter
+type Increaser interface {
+	Increase(diff int)
+}
+
+// commandline option definition
 type Parameter struct {
 	Short       byte
 	Long        string
 	ValueName   string
 	Description string
+	Kind        Kind
+	// textual value applied before the commandline is parsed
+	Default string
+	// environment variable overriding the default
+	Env string
+	// environment variable used if an optional value is omitted
+	ImplicitEnv string
+	// the parameter is accepted by every command
+	Global bool
+	// name of the command run by a trigger
+	Trigger string
+	// increment of a counter per occurrence
+	Step int
+	// completion of the value, see the COMPLETE_* constants
+	Completion string
+	// values offered by the COMPLETE_CHOICE completion
+	Choices []string
+	// *bool, *string, *[]string or Increaser depending on the kind
+	target interface{}
+	// the option occurred on the commandline since the last Reset
+	parsed bool
 }
 
+// check if the option does not take any value
 func (p *Parameter) IsFlag() bool {
-	return 0 == len(p.ValueName)
+	return KIND_BOOL == p.Kind || KIND_COUNTER == p.Kind || KIND_TRIGGER == p.Kind
 }
 
-func (p *Parameter) Matches(value string) bool {
-	short := "-" + string(p.Short)
-	long := "--" + p.Long
+// check if the option requires a value
+func (p *Parameter) RequiresValue() bool {
+	return KIND_STRING == p.Kind || KIND_LIST == p.Kind
+}
 
-	return strings.HasPrefix(value, short) ||
-		strings.HasPrefix(value, long)
+// check if the long option name equals the value
+func (p *Parameter) MatchesLong(name string) bool {
+	return p.Long == name
 }
 
-func (p *Parameter) ParseValue(value string) string {
-	stop := false
-	trim := func(char rune) bool {
-		if stop {
-			return false
-		} else if '=' == char {
-			stop = true
-		}
+// check if the value is an abbreviation of the long option name
+func (p *Parameter) HasPrefix(name string) bool {
+	return 0 < len(name) && strings.HasPrefix(p.Long, name)
+}
 
-		return true
-	}
+// check if the short option name equals the value
+func (p *Parameter) MatchesShort(name byte) bool {
+	return 0 != p.Short && p.Short == name
+}
+
+// set the value to use if the option is missing on the commandline.
+// the value is converted like the value of the environment variable.
+func (p *Parameter) WithDefault(value string) (self *Parameter) {
+	self = p
+	p.Default = value
 
-	return strings.TrimLeftFunc(value, trim)
+	return
 }
 
-func (p *Parameter) ParseValueOr(value string, env string, fallback string) string {
-	result := p.ParseValue(value)
+// set the environment variable to use if the option is missing on
+// the commandline. it takes precedence over the default value.
+func (p *Parameter) WithEnv(name string) (self *Parameter) {
+	self = p
+	p.Env = name
 
-	switch {
-	case 0 < len(result):
-		return result
-	case 0 < len(env):
-		return os.Getenv(env)
-	case 0 < len(fallback):
-		return fallback
-	default:
-		return ""
+	return
+}
+
+// set the environment variable to use if the optional value of the
+// option is omitted
+func (p *Parameter) WithImplicitEnv(name string) (self *Parameter) {
+	self = p
+	p.ImplicitEnv = name
+
+	return
+}
+
+// set the completion of the value. kinds other than the COMPLETE_*
+// constants are listed by the candidates command of the completion
+// script.
+func (p *Parameter) WithCompletion(kind string) (self *Parameter) {
+	self = p
+	p.Completion = kind
+
+	return
+}
+
+// complete the value with one of the fixed values
+func (p *Parameter) WithChoices(values ...string) (self *Parameter) {
+	self = p
+	p.Completion = COMPLETE_CHOICE
+	p.Choices = values
+
+	return
+}
+
+// accept the option for every command
+func (p *Parameter) AsGlobal() (self *Parameter) {
+	self = p
+	p.Global = true
+
+	return
+}
+
+// restore the default value or the value of the environment
+// variable. boolean values are enabled by 1, true, yes or on.
+// counters and triggers are not affected; the environment variable
+// of a counter is expected to be evaluated by its target.
+func (p *Parameter) Reset() {
+	value := p.Default
+	p.parsed = false
+
+	if 0 < len(p.Env) {
+		if env := os.Getenv(p.Env); 0 < len(env) {
+			value = env
+		}
+	}
+
+	switch target := p.target.(type) {
+	case *bool:
+		*target = parseBool(value)
+	case *string:
+		*target = value
+	case *[]string:
+		*target = []string{}
+
+		if 0 < len(value) {
+			*target = strings.Split(value, string(os.PathListSeparator))
+		}
 	}
 }
 
-func (p *Parameter) ParseIntValueOr(value string, fallback int) int {
-	result := p.ParseValue(value)
+// store an occurrence of the option. _hasValue_ indicates whether a
+// value was provided on the commandline at all. the first occurrence
+// of a list option replaces the default and environment values, the
+// following ones are appended.
+func (p *Parameter) Set(value string, hasValue bool) {
+	if KIND_OPTIONAL == p.Kind && false == hasValue && 0 < len(p.ImplicitEnv) {
+		value = os.Getenv(p.ImplicitEnv)
+	}
 
-	if 0 < len(result) {
-		if num, err := strconv.Atoi(result); nil == err {
-			return num
+	switch target := p.target.(type) {
+	case *bool:
+		*target = true
+	case *string:
+		*target = value
+	case *[]string:
+		if false == p.parsed {
+			*target = []string{}
 		}
+
+		*target = append(*target, value)
+	case Increaser:
+		target.Increase(p.Step)
 	}
 
-	return fallback
+	p.parsed = true
 }
 
 func (p *Parameter) Usage() string {
-	long := p.Long
+	var short string = "  "
+	var long string = p.Long + p.valueSuffix()
+	var description string = p.Description
+
+	if 0 != p.Short {
+		short = "-" + string(p.Short) + ","
+	}
+
+	if KIND_LIST == p.Kind {
+		description += " (repeatable)"
+	}
 
-	if false == p.IsFlag() {
-		long = p.Long + "=" + p.ValueName
+	if 0 < len(p.Default) && false == p.IsFlag() {
+		description += " (default: " + p.Default + ")"
 	}
 
-	return fmt.Sprintf("\t-%c, --%-15s %s\n",
-		p.Short,
+	return fmt.Sprintf("\t%s --%-15s %s\n",
+		short,
 		long,
-		p.Description)
+		description)
 }
 
-func NewArgParameter(short byte, long string, argument string, description string) *Parameter {
-	return &Parameter{short, long, argument, description}
+// the option names and the value, e.g. -s, --shell=PATH
+func (p *Parameter) Synopsis() string {
+	long := "--" + p.Long + p.valueSuffix()
+
+	if 0 != p.Short {
+		return "-" + string(p.Short) + ", " + long
+	}
+
+	return long
 }
 
-func NewFlagParameter(short byte, long string, description string) *Parameter {
-	return &Parameter{short, long, "", description}
+func (p *Parameter) String() string {
+	return "--" + p.Long
+}
+
+func (p *Parameter) valueSuffix() string {
+	switch p.Kind {
+	case KIND_OPTIONAL:
+		return "[=" + p.ValueName + "]"
+	case KIND_STRING, KIND_LIST:
+		return "=" + p.ValueName
+	default:
+		return ""
+	}
+}
+
+func parseBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
 }
@@ -8,158 +8,304 @@ import (
 	"gospace"
 )
 
-const (
-	// trigger the _help_ action
-	ACTION_HELP Action = iota
-	// trigger the _version_ action
-	ACTION_VERSION = iota
-	// trigger the _gospace_ action
-	ACTION_GOSPACE = iota
-)
-
-var (
-	version *Parameter
-	help    *Parameter
-	norun   *Parameter
-	blank   *Parameter
-	shell   *Parameter
-	gosdk   *Parameter
-	debug   *Parameter
-)
-
-// typedef for triggers
-type Action int
-
-// action callback
+// command callback
 type Callback func(argv *Arguments) (int, error)
 
 // conversion utility to resolve a (possibly) relative path
 type PathResolver func(path string) (string, error)
 
-// command-line parser
-type Parser struct {
-	gosdkEnv  string
-	shellEnv  string
-	resolver  *PathResolver
-	callbacks map[Action]*Callback
+// invalid commandline, e.g. an unknown option or command
+type UsageError struct {
+	Message string
 }
 
-// register a callback to be invoked when the commandline contains
-// certain action triggers. if the commandline contains multiple
-// triggers, the first encountered (not registered) will be executed.
-func (p *Parser) On(action Action, cb *Callback) (self *Parser) {
-	self = p
-
-	gospace.T("registering callback", action)
-
-	p.callbacks[action] = cb
-
-	return
+// command-line parser
+type Parser struct {
+	resolver *PathResolver
+	root     *Command
+	registry *Registry
+	argv     *Arguments
 }
 
-// process the arguments and call the first matching trigger callback.
+// process the arguments and call the callback of the selected
+// command. the first operand naming a sub-command selects it, options
+// are looked up in the selected command and the global parameters.
 // the return values are most likely from the callback itself, unless
-// an unresolvable directory was provided on the commandline.
+// an unresolvable directory or an invalid option was provided on the
+// commandline.
+// options follow the getopt_long conventions: long options may be
+// abbreviated as long as the prefix is unambiguous, short options can
+// be bundled (-bn) and values are either attached (--shell=PATH,
+// -sPATH) or passed as the next argument (--shell PATH, -s PATH).
+// optional values have to be attached.
 func (p *Parser) Parse(input []string) (status int, err error) {
 	var passthrough bool = false
-	var argv *Arguments = NewArguments()
+	var argv *Arguments = p.argv
+	var trigger *Command
+	var triggered bool
+
+	argv.Reset(p.root)
+	p.registry.Reset()
+
+	gospace.LOG_PARSER.T("processing commandline", input)
 
-	gospace.T("processing commandline", input)
+	for i := 0; i < len(input); i++ {
+		arg := input[i]
 
-	for _, arg := range input {
 		if passthrough {
-			gospace.T("found argument for sub-shell")
+			gospace.LOG_PARSER.T("found argument for sub-shell")
 
 			argv.AppendShellArgument(arg)
-		} else {
-			gospace.T("processing gospace argument", arg)
-
-			switch {
-			case "--" == arg:
-				gospace.T("argument terminator encountered")
-				passthrough = true
-			case help.Matches(arg):
-				gospace.T("help action triggered")
-				return p.fire(ACTION_HELP, argv)
-			case version.Matches(arg):
-				gospace.T("version action triggered")
-				return p.fire(ACTION_VERSION, argv)
-			case norun.Matches(arg):
-				gospace.T("shell spawning is only simulated")
-				argv.NoRun = true
-			case blank.Matches(arg):
-				gospace.T("blank flag defined")
-				argv.Blank = true
-			case debug.Matches(arg):
-				gospace.T("increasing verbosity")
-				// increase by 2, so -vvv will yield full verbosity
-				gospace.LOG_LEVEL.Increase(2)
-			case shell.Matches(arg):
-				gospace.T("custom shell argument")
-				argv.Shell = shell.ParseValueOr(arg, p.shellEnv, "")
-			case gosdk.Matches(arg):
-				gospace.T("custom go installation provided")
-				argv.GoSDK = gosdk.ParseValueOr(arg, p.gosdkEnv, "")
-			case strings.HasPrefix(arg, "-"):
-				return 0, fmt.Errorf("Unknown argument '%s'", arg)
-			default:
-				gospace.T("received directory input for GOPATH")
-				if path, err := (*p.resolver)(arg); nil != err {
-					return 0, err
-				} else {
-					argv.AppendPath(path)
+			continue
+		}
+
+		gospace.LOG_PARSER.T("processing gospace argument", arg)
+
+		switch {
+		case "--" == arg:
+			gospace.LOG_PARSER.T("argument terminator encountered")
+			passthrough = true
+		case strings.HasPrefix(arg, "--"):
+			if trigger, triggered, i, err = p.parseLong(input, i, argv); nil != err {
+				return 0, err
+			} else if triggered {
+				return p.fire(trigger, argv)
+			}
+		case strings.HasPrefix(arg, "-") && 1 < len(arg):
+			if trigger, triggered, i, err = p.parseShort(input, i, argv); nil != err {
+				return 0, err
+			} else if triggered {
+				return p.fire(trigger, argv)
+			}
+		case argv.IsEmpty() && nil != argv.Command.Find(arg):
+			gospace.LOG_PARSER.T("command", arg, "selected")
+			argv.Command = argv.Command.Find(arg)
+		case false == argv.Command.Paths:
+			gospace.LOG_PARSER.T("received command operand")
+			argv.AppendOperand(arg)
+		default:
+			gospace.LOG_PARSER.T("received directory input for GOPATH")
+			if path, err := (*p.resolver)(arg); nil != err {
+				return 0, err
+			} else {
+				argv.AppendPath(path)
+			}
+		}
+	}
+
+	return p.fire(argv.Command, argv)
+}
+
+// process the long option at _input[index]_. the returned index
+// points to the last consumed argument.
+func (p *Parser) parseLong(input []string, index int, argv *Arguments) (*Command, bool, int, error) {
+	var param *Parameter
+	var value string
+	var hasValue bool
+	var err error
+
+	name := strings.TrimPrefix(input[index], "--")
+
+	if separator := strings.Index(name, "="); 0 <= separator {
+		value = name[separator+1:]
+		name = name[:separator]
+		hasValue = true
+	}
+
+	if param, err = p.lookupLong(argv.Command, name); nil != err {
+		return nil, false, index, err
+	}
+
+	switch {
+	case param.IsFlag() && hasValue:
+		return nil, false, index, Usagef("Option '%s' does not take a value", param)
+	case param.RequiresValue() && false == hasValue:
+		if index+1 == len(input) {
+			return nil, false, index, Usagef("Option '%s' requires a value", param)
+		}
+
+		index++
+		value = input[index]
+		hasValue = true
+	}
+
+	action, triggered := p.apply(param, value, hasValue, argv)
+
+	return action, triggered, index, nil
+}
+
+// process the bundle of short options at _input[index]_. the
+// returned index points to the last consumed argument.
+func (p *Parser) parseShort(input []string, index int, argv *Arguments) (*Command, bool, int, error) {
+	bundle := input[index]
+
+	for j := 1; j < len(bundle); j++ {
+		var value string
+		var hasValue bool
+
+		param := p.lookupShort(argv.Command, bundle[j])
+
+		if nil == param {
+			return nil, false, index, Usagef("Unknown option '-%c'", bundle[j])
+		}
+
+		if false == param.IsFlag() {
+			// the remainder of the bundle is the value
+			if j+1 < len(bundle) {
+				value = bundle[j+1:]
+				hasValue = true
+			} else if param.RequiresValue() {
+				if index+1 == len(input) {
+					return nil, false, index, Usagef("Option '-%c' requires a value", param.Short)
 				}
+
+				index++
+				value = input[index]
+				hasValue = true
 			}
+
+			j = len(bundle)
+		}
+
+		if action, triggered := p.apply(param, value, hasValue, argv); triggered {
+			return action, true, index, nil
+		}
+	}
+
+	return nil, false, index, nil
+}
+
+// find the parameter by its long name or an unambiguous prefix
+func (p *Parser) lookupLong(command *Command, name string) (*Parameter, error) {
+	candidates := []*Parameter{}
+
+	for _, param := range p.parametersOf(command) {
+		if param.MatchesLong(name) {
+			return param, nil
+		} else if param.HasPrefix(name) {
+			candidates = append(candidates, param)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, p.unknownLong(command, name)
+	case 1:
+		gospace.LOG_PARSER.T("expanding", name, "to", candidates[0])
+		return candidates[0], nil
+	default:
+		names := []string{}
+
+		for _, candidate := range candidates {
+			names = append(names, candidate.String())
+		}
+
+		return nil, Usagef("Ambiguous option '--%s' (could be %s)",
+			name,
+			strings.Join(names, ", "))
+	}
+}
+
+// the error of an unknown long option. options of other commands are
+// reported as such, otherwise similar options are suggested.
+func (p *Parser) unknownLong(command *Command, name string) error {
+	if param := p.registry.Find(name); nil != param && command == p.root {
+		return Usagef("Option '%s' is not accepted by the default command", param)
+	} else if nil != param {
+		return Usagef("Option '%s' is not accepted by '%s'", param, command.Path())
+	}
+
+	names := []string{}
+
+	for _, param := range p.parametersOf(command) {
+		names = append(names, param.Long)
+	}
+
+	suggestions := []string{}
+
+	for _, suggestion := range gospace.Suggest(name, names) {
+		suggestions = append(suggestions, "--"+suggestion)
+	}
+
+	return Usagef("Unknown option '--%s'%s", name, gospace.DidYouMean(suggestions))
+}
+
+func (p *Parser) lookupShort(command *Command, name byte) *Parameter {
+	for _, param := range p.parametersOf(command) {
+		if param.MatchesShort(name) {
+			return param
 		}
 	}
 
-	return p.fire(ACTION_GOSPACE, argv)
+	return nil
 }
 
-func (p *Parser) fire(action Action, argv *Arguments) (int, error) {
-	if callback, ok := p.callbacks[action]; ok {
-		return (*callback)(argv)
+// store the option value in its target. the result indicates
+// whether the option triggers a command immediately.
+func (p *Parser) apply(param *Parameter, value string, hasValue bool, argv *Arguments) (*Command, bool) {
+	gospace.LOG_PARSER.T("applying option", param)
+
+	if KIND_TRIGGER == param.Kind {
+		gospace.LOG_PARSER.T(param.Trigger, "command triggered")
+		argv.Triggered = true
+		return p.root.Find(param.Trigger), true
 	}
 
-	gospace.T("no callback registered for", action)
+	param.Set(value, hasValue)
+
+	return nil, false
+}
+
+// invoke the callback of the command. the arguments keep the command
+// selected on the commandline, which may differ for triggered
+// commands like _help_.
+func (p *Parser) fire(command *Command, argv *Arguments) (int, error) {
+	if nil != command && nil != command.Callback {
+		return (*command.Callback)(argv)
+	}
+
+	gospace.LOG_PARSER.T("no callback registered for", command)
 
 	return 0, nil
 }
 
-func init() {
-	version = NewFlagParameter('V', "version", "display the application version and exit")
-	help = NewFlagParameter('h', "help", "show this message and exit")
-	norun = NewFlagParameter('n', "dry", "simulates the shell spawning")
-	blank = NewFlagParameter('b', "blank", "overwrite GOPATH instead of extending it")
-	debug = NewFlagParameter('v', "verbose", "raise the verbosity")
-	shell = NewArgParameter('s', "shell", "PATH", "run the workspace in a custom shell")
-	gosdk = NewArgParameter('g', "go", "PATH", "include the go installation in the PATH")
+// the options of the command followed by the global options
+func (p *Parser) parametersOf(command *Command) []*Parameter {
+	return append(append([]*Parameter{}, command.Parameters...), p.registry.Globals()...)
+}
+
+// the command selected by the most recent commandline
+func (p *Parser) Selected() *Command {
+	if nil == p.argv.Command {
+		return p.root
+	}
+
+	return p.argv.Command
 }
 
-// parser instance factory
-func NewParser(sdk string, shell string, resolver *PathResolver) *Parser {
-	callbacks := make(map[Action]*Callback)
+// write the usage of the command followed by the footer
+func (p *Parser) WriteUsage(out io.Writer, application string, command *Command, footer string) {
+	command.WriteUsage(out, application, p.registry.Globals())
 
-	return &Parser{sdk, shell, resolver, callbacks}
+	if 0 < len(footer) {
+		io.WriteString(out, "\n")
+		io.WriteString(out, footer)
+		io.WriteString(out, "\n")
+	}
 }
 
-// write the program header, footer, usage and commandline
-// arguments to the writer.
-func WriteUsage(out io.Writer, application string, description string, footer string) {
-	header := fmt.Sprintf("usage: %s [OPTION]... [PATH]...\n", application)
+func (e *UsageError) Error() string {
+	return e.Message
+}
 
-	io.WriteString(out, header)
-	io.WriteString(out, description)
-	io.WriteString(out, "\n\n")
+// create a UsageError with a formatted message, see fmt.Sprintf
+func Usagef(format string, args ...interface{}) error {
+	return &UsageError{fmt.Sprintf(format, args...)}
+}
 
-	io.WriteString(out, "arguments:\n")
-	io.WriteString(out, blank.Usage())
-	io.WriteString(out, norun.Usage())
-	io.WriteString(out, debug.Usage())
-	io.WriteString(out, gosdk.Usage())
-	io.WriteString(out, shell.Usage())
-	io.WriteString(out, help.Usage())
-	io.WriteString(out, version.Usage())
-	io.WriteString(out, footer)
-	io.WriteString(out, "\n")
+// parser instance factory. the _root_ command is used if the
+// commandline does not select a sub-command. the parameters of the
+// _registry_ are expected to be bound to _argv_ or other variables.
+func NewParser(root *Command, registry *Registry, argv *Arguments, resolver *PathResolver) *Parser {
+	return &Parser{resolver, root, registry, argv}
 }
@@ -0,0 +1,250 @@
+package flag
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// simple counter receiving the verbosity
+type counter int
+
+func (c *counter) Increase(diff int) {
+	*c += counter(diff)
+}
+
+// the values collected by a parse run
+type outcome struct {
+	Command   string
+	Fired     string
+	Blank     bool
+	Blanket   bool
+	NoRun     bool
+	Verbosity int
+	Shell     string
+	GoSDK     string
+	Include   []string
+	Path      []string
+	Operands  []string
+	ShellArgv []string
+}
+
+// parser declaring a subset of the gospace options, including the
+// ambiguous prefixes of --blank and --blanket
+type fixture struct {
+	parser    *Parser
+	argv      *Arguments
+	blanket   bool
+	verbosity counter
+	fired     string
+}
+
+func newFixture() *fixture {
+	f := &fixture{argv: NewArguments()}
+	registry := NewRegistry().WithEnvPrefix("FLAG_TEST_")
+	resolver := PathResolver(func(path string) (string, error) { return "/abs/" + path, nil })
+	launch := Callback(func(argv *Arguments) (int, error) {
+		f.fired = argv.Command.Path()
+		return 0, nil
+	})
+	help := Callback(func(argv *Arguments) (int, error) {
+		f.fired = "help"
+		return 0, nil
+	})
+
+	blank := registry.Bool('b', "blank", "overwrite GOPATH", &f.argv.Blank)
+	blanket := registry.Bool(0, "blanket", "cover everything", &f.blanket)
+	dry := registry.Bool('n', "dry", "simulate", &f.argv.NoRun)
+	shell := registry.String('s', "shell", "PATH", "custom shell", &f.argv.Shell)
+	include := registry.List('I', "include", "DIR", "include the directory", &f.argv.Include)
+	gosdk := registry.Optional('g', "go", "SDK", "go installation", &f.argv.GoSDK).
+		WithImplicitEnv("FLAG_TEST_IMPLICIT_SDK")
+	markdown := registry.Bool(0, "markdown", "markdown output", &f.argv.Markdown)
+
+	registry.Counter('v', "verbose", "raise the verbosity", &f.verbosity, 2).AsGlobal()
+	registry.Trigger('h', "help", "show this message", "help").AsGlobal()
+
+	root := NewCommand("", "[PATH]...", "test", true, &launch, blank, blanket, dry, shell, include, gosdk)
+	root.Add(
+		NewCommand("help", "[COMMAND]...", "help", false, &help, markdown),
+		NewCommand("env", "[PATH]...", "env", true, &launch, blank, include))
+
+	f.parser = NewParser(root, registry, f.argv, &resolver)
+
+	return f
+}
+
+func (f *fixture) outcome() outcome {
+	return outcome{
+		Command:   f.argv.Command.Path(),
+		Fired:     f.fired,
+		Blank:     f.argv.Blank,
+		Blanket:   f.blanket,
+		NoRun:     f.argv.NoRun,
+		Verbosity: int(f.verbosity),
+		Shell:     f.argv.Shell,
+		GoSDK:     f.argv.GoSDK,
+		Include:   f.argv.Include,
+		Path:      f.argv.Path,
+		Operands:  f.argv.Operands,
+		ShellArgv: f.argv.ShellArgv,
+	}
+}
+
+func TestParse(t *testing.T) {
+	os.Setenv("FLAG_TEST_IMPLICIT_SDK", "1.8")
+	defer os.Unsetenv("FLAG_TEST_IMPLICIT_SDK")
+
+	tests := []struct {
+		input    string
+		expected outcome
+	}{
+		{"", outcome{}},
+		{"--blank", outcome{Blank: true}},
+		{"--blanket", outcome{Blanket: true}},
+		{"--blanke", outcome{Blanket: true}},
+		{"--dr", outcome{NoRun: true}},
+		{"-bn", outcome{Blank: true, NoRun: true}},
+		{"-vvv", outcome{Verbosity: 6}},
+		{"-v --verb", outcome{Verbosity: 4}},
+		{"-s /bin/sh", outcome{Shell: "/bin/sh"}},
+		{"-s/bin/sh", outcome{Shell: "/bin/sh"}},
+		{"--shell /bin/sh", outcome{Shell: "/bin/sh"}},
+		{"--shell=/bin/sh", outcome{Shell: "/bin/sh"}},
+		{"--sh=/bin/sh", outcome{Shell: "/bin/sh"}},
+		{"-bns /bin/sh", outcome{Blank: true, NoRun: true, Shell: "/bin/sh"}},
+		{"-bs/bin/sh", outcome{Blank: true, Shell: "/bin/sh"}},
+		{"-I a -I b --include=c", outcome{Include: []string{"a", "b", "c"}}},
+		{"-g", outcome{GoSDK: "1.8"}},
+		{"--go", outcome{GoSDK: "1.8"}},
+		{"-g1.7", outcome{GoSDK: "1.7"}},
+		{"--go=1.7", outcome{GoSDK: "1.7"}},
+		{"--go 1.7", outcome{GoSDK: "1.8", Path: []string{"/abs/1.7"}}},
+		{"proj -b other", outcome{Blank: true, Path: []string{"/abs/proj", "/abs/other"}}},
+		{"proj -- -c ls", outcome{Path: []string{"/abs/proj"}, ShellArgv: []string{"-c", "ls"}}},
+		{"-- -b", outcome{ShellArgv: []string{"-b"}}},
+		{"env -b proj", outcome{Command: "env", Fired: "env", Blank: true, Path: []string{"/abs/proj"}}},
+		{"proj env", outcome{Path: []string{"/abs/proj", "/abs/env"}}},
+		{"help env", outcome{Command: "help", Fired: "help", Operands: []string{"env"}}},
+		{"-b --help proj", outcome{Fired: "help", Blank: true}},
+		{"env -vh", outcome{Command: "env", Fired: "help", Verbosity: 2}},
+	}
+
+	for _, test := range tests {
+		f := newFixture()
+
+		if _, err := f.parser.Parse(strings.Fields(test.input)); nil != err {
+			t.Errorf("parsing '%s' failed: %s", test.input, err)
+			continue
+		}
+
+		if actual := f.outcome(); fmt.Sprintf("%+v", actual) != fmt.Sprintf("%+v", test.expected) {
+			t.Errorf("parsing '%s'\n got: %+v\nwant: %+v", test.input, actual, test.expected)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{"--bla", "Ambiguous option '--bla' (could be --blank, --blanket)"},
+		{"--unknown", "Unknown option '--unknown'"},
+		{"--blnk", "Unknown option '--blnk' (did you mean '--blank'?)"},
+		{"-q", "Unknown option '-q'"},
+		{"-bq", "Unknown option '-q'"},
+		{"--dry=yes", "Option '--dry' does not take a value"},
+		{"--shell", "Option '--shell' requires a value"},
+		{"-s", "Option '-s' requires a value"},
+		{"-I", "Option '-I' requires a value"},
+		{"--markdown", "Option '--markdown' is not accepted by the default command"},
+		{"env --shell=/bin/sh", "Option '--shell' is not accepted by 'env'"},
+	}
+
+	for _, test := range tests {
+		_, err := newFixture().parser.Parse(strings.Fields(test.input))
+
+		if _, ok := err.(*UsageError); false == ok {
+			t.Errorf("parsing '%s' yielded %v instead of a usage error", test.input, err)
+		} else if err.Error() != test.message {
+			t.Errorf("parsing '%s'\n got: %s\nwant: %s", test.input, err, test.message)
+		}
+	}
+}
+
+func TestParseEnvironment(t *testing.T) {
+	os.Setenv("FLAG_TEST_INCLUDE", "x"+string(os.PathListSeparator)+"y")
+	os.Setenv("FLAG_TEST_BLANK", "yes")
+	defer os.Unsetenv("FLAG_TEST_INCLUDE")
+	defer os.Unsetenv("FLAG_TEST_BLANK")
+
+	tests := []struct {
+		input   string
+		blank   bool
+		include []string
+	}{
+		{"", true, []string{"x", "y"}},
+		{"-I a", true, []string{"a"}},
+		{"-I a -I b", true, []string{"a", "b"}},
+		{"", true, []string{"x", "y"}},
+	}
+
+	f := newFixture()
+
+	// a single parser instance, since repeated runs have to start
+	// from the environment values again
+	for _, test := range tests {
+		if _, err := f.parser.Parse(strings.Fields(test.input)); nil != err {
+			t.Errorf("parsing '%s' failed: %s", test.input, err)
+		} else if f.argv.Blank != test.blank || fmt.Sprint(f.argv.Include) != fmt.Sprint(test.include) {
+			t.Errorf("parsing '%s' yielded blank %v and include %v", test.input, f.argv.Blank, f.argv.Include)
+		}
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	var format string
+	var include []string
+	var colour bool
+
+	registry := NewRegistry().WithEnvPrefix("FLAG_TEST_")
+	root := NewCommand("", "", "test", false, nil,
+		registry.String('f', "format", "FORMAT", "output format", &format).WithDefault("text"),
+		registry.List('I', "include", "DIR", "include the directory", &include).
+			WithDefault("a"+string(os.PathListSeparator)+"b"),
+		registry.Bool(0, "colour", "colourful output", &colour).WithDefault("yes"))
+	parser := NewParser(root, registry, NewArguments(), nil)
+
+	os.Setenv("FLAG_TEST_FORMAT", "json")
+	defer os.Unsetenv("FLAG_TEST_FORMAT")
+
+	tests := []struct {
+		input   string
+		format  string
+		include []string
+	}{
+		{"", "json", []string{"a", "b"}},
+		{"-f yaml", "yaml", []string{"a", "b"}},
+		{"-I c", "json", []string{"c"}},
+	}
+
+	for _, test := range tests {
+		if _, err := parser.Parse(strings.Fields(test.input)); nil != err {
+			t.Errorf("parsing '%s' failed: %s", test.input, err)
+		} else if format != test.format || fmt.Sprint(include) != fmt.Sprint(test.include) || false == colour {
+			t.Errorf("parsing '%s' yielded format %q, include %v and colour %v", test.input, format, include, colour)
+		}
+	}
+
+	os.Unsetenv("FLAG_TEST_FORMAT")
+
+	if parser.Parse([]string{}); "text" != format {
+		t.Errorf("expected the default format, got %q", format)
+	}
+
+	if usage := root.Parameters[0].Usage(); false == strings.Contains(usage, "(default: text)") {
+		t.Errorf("expected the default in the usage %q", usage)
+	}
+}
@@ -0,0 +1,107 @@
+package flag
+
+import (
+	"strings"
+)
+
+// ordered collection of parameter declarations. each declaration
+// binds the parameter to the variable receiving its value.
+type Registry struct {
+	parameters []*Parameter
+	envPrefix  string
+}
+
+// derive the environment variable of every parameter declared
+// afterwards from its long name, e.g. GOSPACE_ + --dry-run yields
+// GOSPACE_DRY_RUN. triggers do not have an environment variable.
+func (r *Registry) WithEnvPrefix(prefix string) (self *Registry) {
+	self = r
+	r.envPrefix = prefix
+
+	return
+}
+
+// declare a switch without value
+func (r *Registry) Bool(short byte, long string, description string, target *bool) *Parameter {
+	return r.add(&Parameter{Short: short, Long: long, Description: description, Kind: KIND_BOOL, target: target})
+}
+
+// declare an option with a mandatory value
+func (r *Registry) String(short byte, long string, value string, description string, target *string) *Parameter {
+	return r.add(&Parameter{Short: short, Long: long, ValueName: value, Description: description, Kind: KIND_STRING, target: target})
+}
+
+// declare a repeatable option with a mandatory value. the default
+// and environment values are path-list separated.
+func (r *Registry) List(short byte, long string, value string, description string, target *[]string) *Parameter {
+	return r.add(&Parameter{Short: short, Long: long, ValueName: value, Description: description, Kind: KIND_LIST, target: target})
+}
+
+// declare a repeatable switch. each occurrence increases the target
+// by _step_.
+func (r *Registry) Counter(short byte, long string, description string, target Increaser, step int) *Parameter {
+	return r.add(&Parameter{Short: short, Long: long, Description: description, Kind: KIND_COUNTER, Step: step, target: target})
+}
+
+// declare an option with an optional value
+func (r *Registry) Optional(short byte, long string, value string, description string, target *string) *Parameter {
+	return r.add(&Parameter{Short: short, Long: long, ValueName: value, Description: description, Kind: KIND_OPTIONAL, target: target})
+}
+
+// declare a switch which runs the named command as soon as it is
+// encountered
+func (r *Registry) Trigger(short byte, long string, description string, command string) *Parameter {
+	return r.add(&Parameter{Short: short, Long: long, Description: description, Kind: KIND_TRIGGER, Trigger: command})
+}
+
+// all declarations in order of declaration
+func (r *Registry) Parameters() []*Parameter {
+	return append([]*Parameter{}, r.parameters...)
+}
+
+// the declarations accepted by every command
+func (r *Registry) Globals() []*Parameter {
+	globals := []*Parameter{}
+
+	for _, param := range r.parameters {
+		if param.Global {
+			globals = append(globals, param)
+		}
+	}
+
+	return globals
+}
+
+// find the declaration by its long name
+func (r *Registry) Find(long string) *Parameter {
+	for _, param := range r.parameters {
+		if param.MatchesLong(long) {
+			return param
+		}
+	}
+
+	return nil
+}
+
+// apply the default and environment values of every declaration
+func (r *Registry) Reset() {
+	for _, param := range r.parameters {
+		param.Reset()
+	}
+}
+
+func (r *Registry) add(param *Parameter) *Parameter {
+	if 0 < len(r.envPrefix) && KIND_TRIGGER != param.Kind {
+		name := strings.ToUpper(strings.Replace(param.Long, "-", "_", -1))
+		param.Env = r.envPrefix + name
+	}
+
+	r.parameters = append(r.parameters, param)
+
+	return param
+}
+
+// registry instance factory
+func NewRegistry() *Registry {
+	return &Registry{[]*Parameter{}, ""}
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"gospace"
 
@@ -14,67 +20,824 @@ const (
 	HEADLINE = "shell spawner for go development workspaces"
 	FOOTER   = `commandline parsing can be terminated using --. all remaining values
 will be passed to the shell command.
-if no PATH is specified, the current working directory is used.`
+if no PATH is specified, the current working directory is used.
+every option can also be set via its environment variable GOSPACE_<OPTION>,
+e.g. GOSPACE_SHELL=/bin/bash or GOSPACE_BLANK=1. lists like GOSPACE_INCLUDE
+are separated like PATH. the commandline takes precedence over the
+environment, which takes precedence over the configuration files.`
+	ADD_FOOTER = `the value of --go is optional and therefore has to be attached, e.g.
+gospace add api ~/src/api --go=1.6; a detached value counts as another operand.`
 )
 
+const (
+	// prefix of the environment variables of each option
+	ENV_PREFIX = "GOSPACE_"
+	// completion kind of the go installation names
+	COMPLETE_SDK = "sdk"
+)
+
+// usage examples of the reference documentation
+var EXAMPLES = []*flag.Example{
+	{
+		Commandline: "gospace",
+		Description: "create/rewrite GOPATH to include the PWD as its first element",
+	},
+	{
+		Commandline: "gospace --blank",
+		Description: "set GOPATH to PWD, regardless of the previous value",
+	},
+	{
+		Commandline: "gospace $HOME/goprojects/myproject",
+		Description: "use the provided path as go workspace",
+	},
+	{
+		Commandline: "gospace myproject /usr/lib/go-contrib",
+		Description: `search $CDPATH for a potential directory with that name and use it as
+workspace. /usr/lib/go-contrib will be included in the GOPATH.`,
+	},
+	{
+		Commandline: "gospace --shell=/bin/bash myproject -- --login",
+		Description: `use /bin/bash as the working shell instead of $SHELL. the command is
+invoked with the additional --login argument.`,
+	},
+	{
+		Commandline: "gospace --go=1.6 -- -c \"go test\"",
+		Description: `run the tests of the workspace with the newest installed go 1.6
+release.`,
+	},
+	{
+		Commandline: `eval "$(gospace env myproject)"`,
+		Description: "define the workspace environment in the current shell",
+	},
+}
+
 var commandline *flag.Parser
 var binaryname string
+var settings *gospace.Config
+var resolutions []*gospace.Resolution
 
 func init() {
 	resolver := flag.PathResolver(resolverProxy)
+	registry := flag.NewRegistry().WithEnvPrefix(ENV_PREFIX)
+	arguments := flag.NewArguments()
 
-	commandline = flag.NewParser(gospace.SDK_ENV, gospace.SHELL_ENV, &resolver)
+	commandline = flag.NewParser(commands(registry, arguments), registry, arguments, &resolver)
 	binaryname = path.Base(os.Args[0])
 }
 
+// the parameter declarations and the command tree. the root command
+// spawns the workspace shell.
+func commands(params *flag.Registry, argv *flag.Arguments) *flag.Command {
+	help := flag.Callback(printHelp)
+	version := flag.Callback(printVersion)
+	workspace := flag.Callback(launchWorkspace)
+	sdk := flag.Callback(manageSDKs)
+	sdkList := flag.Callback(listSDKs)
+	env := flag.Callback(exportWorkspace)
+	completion := flag.Callback(printCompletion)
+	which := flag.Callback(explainWorkspaces)
+	recent := flag.Callback(listRecent)
+	add := flag.Callback(addWorkspace)
+	remove := flag.Callback(removeWorkspaces)
+	list := flag.Callback(listWorkspaces)
+
+	blank := params.Bool('b', "blank", "overwrite GOPATH instead of extending it", &argv.Blank)
+	dry := params.Bool('n', "dry", "simulates the shell spawning", &argv.NoRun)
+	format := params.String('f', "format", "FORMAT", "output format of the dry-run and the listings (text or json)", &argv.Format).
+		WithDefault("text").
+		WithChoices("text", "json")
+	exec := params.Bool('x', "exec", "replace gospace with the shell (default if interactive)", &argv.Exec)
+	wait := params.Bool('w', "wait", "keep gospace running until the shell exits", &argv.Wait)
+	include := params.List('I', "include", "DIR", "include the directory in the GOPATH", &argv.Include).
+		WithCompletion(flag.COMPLETE_WORKSPACE)
+	gosdk := params.Optional('g', "go", "SDK", "include the go installation (directory, name or version) in the PATH", &argv.GoSDK).
+		WithImplicitEnv(gospace.SDK_ENV).
+		WithCompletion(COMPLETE_SDK)
+	shell := params.String('s', "shell", "PATH", "run the workspace in a custom shell", &argv.Shell).
+		WithCompletion(flag.COMPLETE_COMMAND)
+	pick := params.Bool('p', "pick", "select the workspace from an interactive list", &argv.Pick)
+
+	// the documentation formats are not selectable via the environment
+	man := params.Bool(0, "man", "print the reference documentation as roff man page", &argv.Man).WithEnv("")
+	markdown := params.Bool(0, "markdown", "print the reference documentation as markdown", &argv.Markdown).WithEnv("")
+
+	params.Counter('v', "verbose", "raise the verbosity", &gospace.LOG_LEVEL, 2).AsGlobal()
+	params.Trigger('h', "help", "show this message and exit", "help").AsGlobal()
+	params.Trigger('V', "version", "display the application version and exit", "version").AsGlobal()
+
+	shellParams := []*flag.Parameter{blank, dry, format, exec, wait, include, gosdk, shell, pick}
+	root := flag.NewCommand("", "[OPTION]... [PATH]...", HEADLINE, true, &workspace, shellParams...)
+
+	root.Add(
+		flag.NewCommand("shell", "[OPTION]... [PATH]...",
+			"spawn a shell in the workspace (default command)",
+			true, &workspace, shellParams...),
+		flag.NewCommand("env", "[OPTION]... [PATH]...",
+			"print the workspace environment in the syntax of the --shell dialect",
+			true, &env, blank, include, gosdk, shell),
+		flag.NewCommand("which", "[OPTION]... NAME...",
+			"show every location tried while resolving the workspace names",
+			false, &which, format),
+		flag.NewCommand("recent", "[OPTION]...",
+			"list the launched workspaces, most frequently and recently used first",
+			false, &recent, format),
+		flag.NewCommand("add", "[OPTION]... NAME [PATH]",
+			"register the workspace root (default: the working directory) under the name",
+			false, &add, include, gosdk, shell),
+		flag.NewCommand("rm", "NAME...",
+			"remove the named workspaces from the registry",
+			false, &remove),
+		flag.NewCommand("ls", "[OPTION]...",
+			"list the named workspaces",
+			false, &list, format),
+		flag.NewCommand("sdk", "[COMMAND]",
+			"manage the installed go versions",
+			false, &sdk).Add(
+			flag.NewCommand("list", "",
+				"list the discovered go installations",
+				false, &sdkList)),
+		flag.NewCommand("help", "[COMMAND]...",
+			"show the usage of a command",
+			false, &help, man, markdown),
+		flag.NewCommand("version", "",
+			"print the gospace command version",
+			false, &version),
+		flag.NewCommand("completion", "SHELL",
+			"print the completion script of the shell (bash, zsh or fish)",
+			false, &completion).WithChoices(flag.COMPLETION_SHELLS...))
+
+	root.Find("help").WithChoices(root.Names()...)
+
+	return root
+}
+
 func main() {
 	var err error = nil
 	var code int = 0
 
-	help := flag.Callback(printHelp)
-	version := flag.Callback(printVersion)
-	workspace := flag.Callback(launchWorkspace)
+	if settings, err = gospace.LoadConfigLayers(); nil != err {
+		printError(err)
+		os.Exit(exitCode(err))
+	}
 
-	commandline.
-		On(flag.ACTION_HELP, &help).
-		On(flag.ACTION_VERSION, &version).
-		On(flag.ACTION_GOSPACE, &workspace)
+	settings.Apply()
 
 	if code, err = commandline.Parse(os.Args[1:]); nil != err {
-		fmt.Println(err.Error())
+		printError(err)
+
+		// the parser itself does not assign exit codes
+		if gospace.EXIT_SUCCESS == code {
+			code = exitCode(err)
+		}
 	}
 
 	os.Exit(code)
 }
 
+// report the error on stderr along with a pointer to the usage of
+// the selected command
+func printError(err error) {
+	command := strings.TrimSpace(binaryname + " " + commandline.Selected().Path())
+
+	fmt.Fprintf(os.Stderr, "%s: %s\n", binaryname, err.Error())
+	fmt.Fprintf(os.Stderr, "Try '%s --help' for more information.\n", command)
+}
+
+// the exit code of the error, see the gospace.EXIT_* constants
+func exitCode(err error) int {
+	var usage *flag.UsageError
+
+	if errors.As(err, &usage) {
+		return gospace.EXIT_USAGE
+	}
+
+	return gospace.ExitCode(err)
+}
+
+// the exit code and the error as callback result
+func fail(err error) (int, error) {
+	return exitCode(err), err
+}
+
+// the callback result of a shell which could not be started. errors
+// without a dedicated exit code yield EXIT_LAUNCH.
+func launchFailure(err error) (int, error) {
+	if code := exitCode(err); gospace.EXIT_FAILURE != code {
+		return code, err
+	}
+
+	return gospace.EXIT_LAUNCH, err
+}
+
+// resolve the path and record the resolution for the dry-run. names
+// matching several directories are offered for selection if the
+// command runs interactively.
 func resolverProxy(path string) (string, error) {
-	return gospace.ResolveGospace(path)
+	resolution, err := gospace.ExplainGospace(path)
+
+	if ambiguous, ok := err.(*gospace.ErrAmbiguousWorkspace); ok && gospace.CanPick() {
+		resolution, err = pickWorkspace(path, ambiguous.Candidates)
+	}
+
+	if nil != err {
+		return "", err
+	}
+
+	resolutions = append(resolutions, resolution)
+
+	return resolution.Path, nil
+}
+
+// let the user select one of the directories
+func pickWorkspace(input string, candidates []string) (*gospace.Resolution, error) {
+	if path, err := gospace.NewPicker(candidates).Pick(); nil != err {
+		return nil, err
+	} else {
+		return &gospace.Resolution{Input: input, Path: path, Source: gospace.SOURCE_PICKER}, nil
+	}
 }
 
+// select the workspace root among the directories within the lookup
+// directories. the selection precedes the paths of the commandline.
+func pickRoot(params *flag.Arguments) error {
+	if false == gospace.CanPick() {
+		return flag.Usagef("Option '--pick' requires stdin and stdout to be a terminal")
+	}
+
+	candidates, err := gospace.PickGospaces()
+
+	if nil != err {
+		return err
+	}
+
+	resolution, err := pickWorkspace("--pick", candidates)
+
+	if nil != err {
+		return err
+	}
+
+	params.Path = append([]string{resolution.Path}, params.Path...)
+	resolutions = append([]*gospace.Resolution{resolution}, resolutions...)
+
+	return nil
+}
+
+// print the usage of the command selected on the commandline. the
+// _help_ command itself takes the command path as operands.
+// --man and --markdown document the whole command tree instead.
 func printHelp(params *flag.Arguments) (int, error) {
-	flag.WriteUsage(os.Stdout, binaryname, HEADLINE, FOOTER)
+	topic := params.Command
+	footer := ""
+
+	if params.Man || params.Markdown {
+		return printManual(params)
+	}
+
+	// help --help describes the help command itself
+	if "help" == topic.Name && false == params.Triggered {
+		if topic = topic.Root().Lookup(params.Operands); nil == topic {
+			return fail(unknownCommand(params.Command.Root(), params.Operands))
+		}
+	}
+
+	if topic == topic.Root() || "shell" == topic.Name {
+		footer = FOOTER
+	} else if "add" == topic.Name {
+		footer = ADD_FOOTER
+	}
+
+	commandline.WriteUsage(os.Stdout, binaryname, topic, footer)
 
 	return 0, nil
 }
 
+func printManual(params *flag.Arguments) (int, error) {
+	var err error
+
+	manual := &flag.Manual{
+		Application: binaryname,
+		Version:     gospace.VERSION,
+		Headline:    HEADLINE,
+		Description: FOOTER,
+		Examples:    EXAMPLES,
+	}
+
+	if params.Man {
+		err = commandline.WriteManPage(os.Stdout, manual)
+	} else {
+		err = commandline.WriteMarkdown(os.Stdout, manual)
+	}
+
+	if nil != err {
+		return fail(err)
+	}
+
+	return 0, nil
+}
+
+// the error of an unknown command path. the sub-commands of the last
+// known command are suggested.
+func unknownCommand(command *flag.Command, names []string) error {
+	parent := command
+
+	for _, name := range names {
+		if next := parent.Find(name); nil != next {
+			parent = next
+			continue
+		}
+
+		return flag.Usagef("Unknown command '%s'%s",
+			strings.TrimSpace(parent.Path()+" "+name),
+			gospace.DidYouMean(gospace.Suggest(name, parent.Names())))
+	}
+
+	return flag.Usagef("Unknown command '%s'", strings.Join(names, " "))
+}
+
 func printVersion(params *flag.Arguments) (int, error) {
 	fmt.Println(binaryname, gospace.VERSION)
 
 	return 0, nil
 }
 
+// the workspace root is the first path on the commandline or the
+// current working directory. its configuration file is layered on
+// top of the user and system configuration and is the source for
+// every value missing on the commandline.
+func loadConfig(params *flag.Arguments) (*gospace.Config, error) {
+	root := gospace.WS_DEFAULT
+
+	if 0 < len(params.Path) {
+		root = params.Path[0]
+	}
+
+	if cfg, err := gospace.LoadWorkspaceConfig(root); nil != err {
+		return nil, err
+	} else {
+		return settings.Merge(cfg), nil
+	}
+}
+
+// the workspace paths of the commandline followed by the resolved
+// --include directories. the current working directory is the
+// workspace root if only includes are given.
+func workspacePaths(params *flag.Arguments) ([]string, error) {
+	paths := append([]string{}, params.Path...)
+
+	if 0 == len(paths) && 0 < len(params.Include) {
+		paths = append(paths, gospace.WS_DEFAULT)
+	}
+
+	for _, include := range params.Include {
+		if path, err := resolverProxy(include); nil != err {
+			return nil, err
+		} else {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths, nil
+}
+
 func launchWorkspace(params *flag.Arguments) (int, error) {
 	var sh *gospace.Shell
 	var ws *gospace.Workspace
+	var cfg *gospace.Config
+	var paths []string
 	var err error
 
-	if sh, err = gospace.ResolveShell(params.Shell, params.ShellArgv); nil != err {
-		return 1, err
-	} else if ws, err = gospace.ParseWorkspace(params.Path, params.GoSDK, !params.Blank); nil != err {
-		return 2, err
+	if params.Pick {
+		if err = pickRoot(params); nil != err {
+			return fail(err)
+		}
+	}
+
+	if cfg, err = loadConfig(params); nil != err {
+		return fail(err)
+	} else if sh, err = gospace.ResolveShell(cfg.ShellOr(params.Shell), cfg.ShellArgsOr(params.ShellArgv)); nil != err {
+		return fail(err)
+	} else if paths, err = workspacePaths(params); nil != err {
+		return fail(err)
+	} else if ws, err = gospace.ParseWorkspace(paths, params.GoSDK, !params.Blank, cfg); nil != err {
+		return fail(err)
+	}
+
+	if err = ws.VerifyGOROOT(); nil != err {
+		fmt.Fprintf(os.Stderr, "%s: warning: %s\n", binaryname, err.Error())
+	}
+
+	if params.NoRun {
+		return printPlan(params, sh, ws, cfg)
+	}
+
+	recordLaunch(ws)
+
+	if replaceProcess(params, sh) {
+		// only returns if the shell could not be executed
+		return launchFailure(sh.Exec(ws))
 	} else if err = sh.Launch(ws, params.NoRun); nil != err {
-		return 4, err
+		if status, ok := gospace.ExitStatus(err); ok {
+			gospace.LOG_SHELL.D("shell exited with status", status)
+			return status, nil
+		}
+
+		return launchFailure(err)
+	}
+
+	return 0, nil
+}
+
+// count the launch of the workspace in the history. failures are
+// reported, but do not prevent the launch.
+func recordLaunch(ws *gospace.Workspace) {
+	history := gospace.UserHistory()
+
+	if root, err := filepath.Abs(ws.Root); nil != err {
+		gospace.LOG_WORKSPACE.W("unable to record workspace", ws.Root, err)
+	} else {
+		history.Record(root, time.Now())
+	}
+
+	if err := history.Save(); nil != err {
+		gospace.LOG_WORKSPACE.W("unable to update history", history.File, err)
+	}
+}
+
+// interactive shells replace the gospace process unless --wait is
+// given. --exec forces the replacement.
+func replaceProcess(params *flag.Arguments, sh *gospace.Shell) bool {
+	if false == gospace.CanReplace() {
+		return false
+	} else if params.Exec {
+		return true
+	}
+
+	return false == params.Wait && sh.IsInteractive()
+}
+
+// describe the shell launch without running the shell. the paths
+// contain the commandline lookups, the configured includes and the
+// implicit workspace root.
+func printPlan(params *flag.Arguments, sh *gospace.Shell, ws *gospace.Workspace, cfg *gospace.Config) (int, error) {
+	paths := append([]*gospace.Resolution{}, resolutions...)
+
+	if 0 == len(params.Path) {
+		paths = append(paths, &gospace.Resolution{Input: ".", Path: ws.Root, Source: gospace.SOURCE_DEFAULT})
 	}
 
+	for _, include := range cfg.Include {
+		paths = append(paths, &gospace.Resolution{Input: include, Path: include, Source: gospace.SOURCE_CONFIG})
+	}
+
+	plan := gospace.NewPlan(sh, ws, gospace.CurrentEnvironment(), paths)
+
+	switch params.Format {
+	case "", "text":
+		return 0, plan.WriteText(os.Stdout)
+	case "json":
+		return 0, plan.WriteJSON(os.Stdout)
+	default:
+		return fail(flag.Usagef("Unknown format '%s'", params.Format))
+	}
+}
+
+// print the workspace environment as statements of the shell
+// dialect given via --shell or SHELL.
+func exportWorkspace(params *flag.Arguments) (int, error) {
+	var dialect gospace.Dialect
+	var ws *gospace.Workspace
+	var cfg *gospace.Config
+	var paths []string
+	var err error
+
+	shell := params.Shell
+
+	if 0 == len(shell) {
+		shell = os.Getenv(gospace.SHELL_ENV)
+	}
+
+	if dialect, err = gospace.LookupDialect(shell); nil != err {
+		return fail(err)
+	} else if cfg, err = loadConfig(params); nil != err {
+		return fail(err)
+	} else if paths, err = workspacePaths(params); nil != err {
+		return fail(err)
+	} else if ws, err = gospace.ParseWorkspace(paths, params.GoSDK, !params.Blank, cfg); nil != err {
+		return fail(err)
+	} else if err = ws.Export(os.Stdout, dialect); nil != err {
+		return fail(err)
+	}
+
+	return 0, nil
+}
+
+// print how each workspace name resolves: every candidate location in
+// order of precedence, whether it exists and which one was selected.
+// the candidates are printed even if none of them exists.
+func explainWorkspaces(params *flag.Arguments) (int, error) {
+	var failure error
+
+	explanations := []*gospace.Resolution{}
+
+	if 0 == len(params.Operands) {
+		return fail(flag.Usagef("Expected at least one workspace name"))
+	}
+
+	for _, name := range params.Operands {
+		resolution, err := gospace.ExplainGospace(name)
+
+		if notFound, ok := err.(*gospace.ErrWorkspaceNotFound); ok {
+			resolution = &gospace.Resolution{Input: name, Candidates: notFound.Searched}
+		} else if ambiguous, ok := err.(*gospace.ErrAmbiguousWorkspace); ok {
+			resolution = &gospace.Resolution{Input: name, Candidates: ambiguous.Searched}
+		} else if nil != err {
+			return fail(err)
+		}
+
+		if nil == failure {
+			failure = err
+		}
+
+		explanations = append(explanations, resolution)
+	}
+
+	switch params.Format {
+	case "", "text":
+		writeExplanations(explanations)
+	case "json":
+		if data, err := json.MarshalIndent(explanations, "", "  "); nil != err {
+			return fail(err)
+		} else {
+			fmt.Println(string(data))
+		}
+	default:
+		return fail(flag.Usagef("Unknown format '%s'", params.Format))
+	}
+
+	if nil != failure {
+		return fail(failure)
+	}
+
+	return 0, nil
+}
+
+// one table of candidates per name. the selected candidate is marked
+// with an asterisk.
+func writeExplanations(explanations []*gospace.Resolution) {
+	table := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+
+	for i, resolution := range explanations {
+		if 0 < i {
+			fmt.Fprintln(table)
+		}
+
+		if 0 < len(resolution.Path) {
+			fmt.Fprintf(table, "%s\n", resolution)
+		} else if isAmbiguous(resolution) {
+			fmt.Fprintf(table, "%s -> ambiguous\n", resolution.Input)
+		} else {
+			fmt.Fprintf(table, "%s -> not found\n", resolution.Input)
+		}
+
+		for _, candidate := range resolution.Candidates {
+			marker := " "
+			state := "missing"
+
+			if candidate.Exists {
+				state = "exists"
+			}
+
+			if candidate.Exists && candidate.Path == resolution.Path {
+				marker = "*"
+			}
+
+			fmt.Fprintf(table, "%s %s\t%s\t%s\n", marker, candidate.Source, candidate.Path, state)
+		}
+	}
+
+	table.Flush()
+}
+
+// check if the unresolved name has existing candidates, i.e. several
+// directories matched equally well
+func isAmbiguous(resolution *gospace.Resolution) bool {
+	for _, candidate := range resolution.Candidates {
+		if candidate.Exists {
+			return true
+		}
+	}
+
+	return false
+}
+
+// print the launched workspaces in descending order of frecency
+func listRecent(params *flag.Arguments) (int, error) {
+	entries := gospace.UserHistory().Ranked(time.Now())
+
+	switch params.Format {
+	case "", "text":
+		table := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+
+		for _, entry := range entries {
+			fmt.Fprintf(table, "%d\t%s\t%s\n", entry.Count, entry.LastUsed.Format("2006-01-02 15:04"), entry.Path)
+		}
+
+		table.Flush()
+	case "json":
+		if data, err := json.MarshalIndent(entries, "", "  "); nil != err {
+			return fail(err)
+		} else {
+			fmt.Println(string(data))
+		}
+	default:
+		return fail(flag.Usagef("Unknown format '%s'", params.Format))
+	}
+
+	return 0, nil
+}
+
+// register the workspace root along with the include paths, the go
+// installation and the shell of the commandline
+func addWorkspace(params *flag.Arguments) (int, error) {
+	var err error
+
+	if 1 > len(params.Operands) || 2 < len(params.Operands) {
+		return fail(flag.Usagef("Expected a name and an optional path (got %d operands)", len(params.Operands)))
+	}
+
+	ws := &gospace.NamedWorkspace{Name: params.Operands[0], Path: gospace.WS_DEFAULT, Go: params.GoSDK, Shell: params.Shell}
+
+	if 2 == len(params.Operands) {
+		ws.Path = params.Operands[1]
+	}
+
+	if err = validateName(params.Command.Root(), ws.Name); nil != err {
+		return fail(err)
+	} else if ws.Path, err = gospace.ResolveGospace(ws.Path); nil != err {
+		return fail(err)
+	} else if ws.Path, err = filepath.Abs(ws.Path); nil != err {
+		return fail(err)
+	}
+
+	for _, include := range params.Include {
+		if path, err := gospace.ResolveGospace(include); nil != err {
+			return fail(err)
+		} else if path, err = filepath.Abs(path); nil != err {
+			return fail(err)
+		} else {
+			ws.Include = append(ws.Include, path)
+		}
+	}
+
+	registry, err := gospace.UserWorkspaceRegistry()
+
+	if nil != err {
+		return fail(err)
+	} else if err = registry.Add(ws); nil != err {
+		return fail(err)
+	} else if err = registry.Save(); nil != err {
+		return fail(err)
+	}
+
+	gospace.LOG_CONFIG.I("registered workspace", ws)
+
+	return 0, nil
+}
+
+// workspace names must be usable as the first operand: neither paths,
+// options nor command names are accepted
+func validateName(root *flag.Command, name string) error {
+	switch {
+	case 0 == len(name):
+		return flag.Usagef("The workspace name must not be empty")
+	case strings.HasPrefix(name, "-") || strings.HasPrefix(name, "."):
+		return flag.Usagef("Invalid workspace name '%s' (must not start with '-' or '.')", name)
+	case strings.ContainsRune(name, filepath.Separator):
+		return flag.Usagef("Invalid workspace name '%s' (must not contain '%c')", name, filepath.Separator)
+	case nil != root.Find(name):
+		return flag.Usagef("Invalid workspace name '%s' (reserved for the command)", name)
+	}
+
+	return nil
+}
+
+// remove the named workspaces from the registry. nothing is removed
+// if any of the names is unknown.
+func removeWorkspaces(params *flag.Arguments) (int, error) {
+	if 0 == len(params.Operands) {
+		return fail(flag.Usagef("Expected at least one workspace name"))
+	}
+
+	registry, err := gospace.UserWorkspaceRegistry()
+
+	if nil != err {
+		return fail(err)
+	}
+
+	for _, name := range params.Operands {
+		if err = registry.Remove(name); nil != err {
+			return fail(err)
+		}
+	}
+
+	if err = registry.Save(); nil != err {
+		return fail(err)
+	}
+
+	return 0, nil
+}
+
+// print the named workspaces in alphabetical order
+func listWorkspaces(params *flag.Arguments) (int, error) {
+	registry, err := gospace.UserWorkspaceRegistry()
+
+	if nil != err {
+		return fail(err)
+	}
+
+	switch params.Format {
+	case "", "text":
+		table := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+
+		for _, ws := range registry.Workspaces {
+			settings := []string{}
+
+			if 0 < len(ws.Include) {
+				settings = append(settings, "include="+strings.Join(ws.Include, string(os.PathListSeparator)))
+			}
+
+			if 0 < len(ws.Go) {
+				settings = append(settings, "go="+ws.Go)
+			}
+
+			if 0 < len(ws.Shell) {
+				settings = append(settings, "shell="+ws.Shell)
+			}
+
+			if 0 < len(settings) {
+				fmt.Fprintf(table, "%s\t%s\t%s\n", ws.Name, ws.Path, strings.Join(settings, " "))
+			} else {
+				fmt.Fprintf(table, "%s\t%s\n", ws.Name, ws.Path)
+			}
+		}
+
+		table.Flush()
+	case "json":
+		if data, err := json.MarshalIndent(registry.Workspaces, "", "  "); nil != err {
+			return fail(err)
+		} else {
+			fmt.Println(string(data))
+		}
+	default:
+		return fail(flag.Usagef("Unknown format '%s'", params.Format))
+	}
+
+	return 0, nil
+}
+
+// print the completion script of the shell. the script itself runs
+// the command with a completion kind instead of a shell to list the
+// workspace and go installation names.
+func printCompletion(params *flag.Arguments) (int, error) {
+	if 1 != len(params.Operands) {
+		return fail(flag.Usagef("Expected exactly one shell (supported: %s)", strings.Join(flag.COMPLETION_SHELLS, ", ")))
+	}
+
+	switch params.Operands[0] {
+	case flag.COMPLETE_WORKSPACE:
+		for _, name := range gospace.ListGospaces() {
+			fmt.Println(name)
+		}
+	case COMPLETE_SDK:
+		for _, sdk := range gospace.DiscoverSDKs(settings).List() {
+			fmt.Println(sdk.Name)
+		}
+	default:
+		if err := commandline.WriteCompletion(os.Stdout, params.Operands[0], binaryname, "completion"); nil != err {
+			return fail(err)
+		}
+	}
+
+	return 0, nil
+}
+
+func manageSDKs(params *flag.Arguments) (int, error) {
+	if 0 == len(params.Operands) || "list" == params.Operands[0] {
+		return listSDKs(params)
+	}
+
+	return fail(unknownCommand(params.Command, params.Operands))
+}
+
+func listSDKs(params *flag.Arguments) (int, error) {
+	registry := gospace.DiscoverSDKs(settings)
+	table := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+
+	for _, sdk := range registry.List() {
+		fmt.Fprintf(table, "%s\t%s\t%s\n", sdk.Name, sdk.Version, sdk.Root)
+	}
+
+	table.Flush()
+
 	return 0, nil
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"cli/gospace/flag"
+)
+
+func TestValidateName(t *testing.T) {
+	root := commands(flag.NewRegistry(), flag.NewArguments())
+
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"web", true},
+		{"my-project", true},
+		{"v1.2", true},
+		{"", false},
+		{"-web", false},
+		{".web", false},
+		{"src/web", false},
+		{"add", false},
+		{"rm", false},
+		{"ls", false},
+		{"help", false},
+		{"completion", false},
+	}
+
+	for _, test := range tests {
+		err := validateName(root, test.name)
+
+		if test.valid && nil != err {
+			t.Errorf("%q should be valid: %s", test.name, err)
+		} else if false == test.valid && nil == err {
+			t.Errorf("%q should be reserved or invalid", test.name)
+		} else if _, ok := err.(*flag.UsageError); nil != err && false == ok {
+			t.Errorf("%q should yield a usage error, got %T", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package gospace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// name of the per-workspace configuration file
+	CONFIG_FILE string = ".gospace"
+	// name of the user and system configuration files
+	CONFIG_NAME string = "config"
+	// environment variable containing shared configuration files
+	CONFIG_PATH_ENV string = "GOSPACE_CONFIG_PATH"
+	// environment variable pointing to the user configuration root
+	XDG_CONFIG_ENV string = "XDG_CONFIG_HOME"
+	// environment variable pointing to the user home directory
+	HOME_ENV string = "HOME"
+)
+
+var (
+	// system-wide configuration file
+	SYSTEM_CONFIG string = "/etc/gospace/config"
+)
+
+// workspace settings which can be committed alongside the sources.
+// the file is expected to contain a JSON object, e.g.
+//
+//	{
+//	    "include": ["../vendor", "/usr/lib/go-contrib"],
+//	    "go": "/opt/go-gae",
+//	    "shell": "/bin/bash",
+//	    "shell_args": ["--login"],
+//	    "env": {"GO15VENDOREXPERIMENT": "1"}
+//	}
+//
+// the user and system configuration files share the format. they
+// may additionally define the defaults _default_shell_, _spaces_,
+// _resolve_order_ and _verbose_.
+type Config struct {
+	// additional GOPATH directories. relative entries are resolved
+	// against the directory containing the configuration file.
+	Include []string `json:"include,omitempty"`
+	// go installation directory
+	Go string `json:"go,omitempty"`
+	// shell binary
+	Shell string `json:"shell,omitempty"`
+	// arguments passed to the shell binary
+	ShellArgs []string `json:"shell_args,omitempty"`
+	// additional environment variables for the shell
+	Env map[string]string `json:"env,omitempty"`
+	// fallback shell if neither the commandline nor SHELL define one
+	DefaultShell string `json:"default_shell,omitempty"`
+	// additional workspace lookup directories
+	Spaces []string `json:"spaces,omitempty"`
+	// verbosity level name or number
+	Verbose string `json:"verbose,omitempty"`
+	// named go installations, e.g. {"gae": "/opt/go_appengine"}
+	SDKs map[string]string `json:"sdks,omitempty"`
+	// additional glob patterns to search for go installations
+	SDKPaths []string `json:"sdk_paths,omitempty"`
+	// names of the workspace resolution sources in order of precedence
+	ResolveOrder []string `json:"resolve_order,omitempty"`
+}
+
+// overlay the values of _other_ on top of the current values and
+// return the result as a new instance. scalar values are replaced
+// if _other_ defines them, lists are appended and environment
+// variables are merged.
+func (c *Config) Merge(other *Config) *Config {
+	merged := NewConfig()
+
+	for _, layer := range []*Config{c, other} {
+		if nil == layer {
+			continue
+		}
+
+		merged.Include = append(merged.Include, layer.Include...)
+		merged.Spaces = append(merged.Spaces, layer.Spaces...)
+		merged.SDKPaths = append(merged.SDKPaths, layer.SDKPaths...)
+		merged.Go = firstNonEmpty(layer.Go, merged.Go)
+		merged.Shell = firstNonEmpty(layer.Shell, merged.Shell)
+		merged.DefaultShell = firstNonEmpty(layer.DefaultShell, merged.DefaultShell)
+		merged.Verbose = firstNonEmpty(layer.Verbose, merged.Verbose)
+
+		if 0 < len(layer.ShellArgs) {
+			merged.ShellArgs = layer.ShellArgs
+		}
+
+		if 0 < len(layer.ResolveOrder) {
+			merged.ResolveOrder = layer.ResolveOrder
+		}
+
+		for name, value := range layer.Env {
+			merged.Env[name] = value
+		}
+
+		for name, value := range layer.SDKs {
+			merged.SDKs[name] = value
+		}
+	}
+
+	return merged
+}
+
+// replace the package defaults with the configured values. the
+// verbosity is only changed if it was not defined via environment.
+func (c *Config) Apply() {
+	if 0 < len(c.DefaultShell) {
+		LOG_CONFIG.T("default shell set to", c.DefaultShell)
+		SHELL_DEFAULT = c.DefaultShell
+	}
+
+	if 0 < len(c.Spaces) {
+		LOG_CONFIG.T("additional lookup directories", c.Spaces)
+		SPACES_DEFAULT = c.Spaces
+	}
+
+	if 0 < len(c.ResolveOrder) {
+		LOG_CONFIG.T("resolution order set to", c.ResolveOrder)
+		RESOLVE_ORDER = c.ResolveOrder
+	}
+
+	if 0 < len(c.Verbose) && 0 == len(os.Getenv(LOGGING_ENV)) {
+		SetVerbosity(c.Verbose)
+
+		LOG_CONFIG.D("verbosity set to", LOG_LEVEL, LOG_CATEGORIES, "via configuration")
+	}
+}
+
+// return the shell path unless the value is empty, in which case the
+// configured shell is returned.
+func (c *Config) ShellOr(path string) string {
+	if 0 < len(path) {
+		return path
+	}
+
+	return c.Shell
+}
+
+// return the shell arguments unless the slice is empty, in which
+// case the configured shell arguments are returned.
+func (c *Config) ShellArgsOr(args []string) []string {
+	if 0 < len(args) {
+		return args
+	}
+
+	return c.ShellArgs
+}
+
+func (c *Config) String() string {
+	return fmt.Sprintf("Config(go=%s, shell=%s, include=%v)",
+		c.Go,
+		c.Shell,
+		c.Include)
+}
+
+// create an empty configuration
+func NewConfig() *Config {
+	return &Config{
+		Include:   []string{},
+		ShellArgs: []string{},
+		Env:       map[string]string{},
+		Spaces:    []string{},
+		SDKs:      map[string]string{},
+		SDKPaths:  []string{},
+	}
+}
+
+// read the configuration from the given file. relative include
+// directories are converted to absolute paths using the directory
+// of the file as base. the error is an ErrPermission if the file is
+// not readable, otherwise an ErrInvalidConfig.
+func LoadConfig(file string) (*Config, error) {
+	var config *Config = NewConfig()
+
+	LOG_CONFIG.T("reading configuration", file)
+
+	if data, err := ioutil.ReadFile(file); nil != err && os.IsPermission(err) {
+		return nil, &ErrPermission{file, err}
+	} else if nil != err {
+		return nil, &ErrInvalidConfig{file, err}
+	} else if err = json.Unmarshal(data, config); nil != err {
+		return nil, &ErrInvalidConfig{file, err}
+	} else if config.ResolveOrder, err = ParseResolveOrder(config.ResolveOrder); nil != err {
+		return nil, &ErrInvalidConfig{file, err}
+	}
+
+	base := filepath.Dir(file)
+
+	absolutePaths(config.Include, base)
+	absolutePaths(config.Spaces, base)
+	absolutePaths(config.SDKPaths, base)
+
+	return config, nil
+}
+
+// read the configuration file of the workspace located at _root_.
+// a missing file is not considered an error; an empty configuration
+// is returned instead. if the root is registered as named workspace,
+// its settings take precedence over the file.
+func LoadWorkspaceConfig(root string) (*Config, error) {
+	config, err := loadWorkspaceFile(root)
+
+	if nil != err {
+		return nil, err
+	}
+
+	registry, err := UserWorkspaceRegistry()
+
+	if nil != err {
+		return nil, err
+	}
+
+	if abs, err := filepath.Abs(root); nil == err {
+		if ws := registry.FindPath(abs); nil != ws {
+			LOG_CONFIG.D("using settings of the named workspace", ws.Name)
+			config = config.Merge(ws.Config())
+		}
+	}
+
+	return config, nil
+}
+
+func loadWorkspaceFile(root string) (*Config, error) {
+	file := filepath.Join(root, CONFIG_FILE)
+
+	if false == PathExists(file) {
+		LOG_CONFIG.T("no configuration found in", root)
+		return NewConfig(), nil
+	}
+
+	LOG_CONFIG.D("using workspace configuration", file)
+
+	return LoadConfig(file)
+}
+
+// list the user and system configuration files in ascending order
+// of precedence: the system configuration, each entry of
+// GOSPACE_CONFIG_PATH and finally the user configuration in
+// XDG_CONFIG_HOME (or ~/.config). entries of GOSPACE_CONFIG_PATH
+// may either be files or directories containing a _config_ file.
+func ConfigLayers() []string {
+	layers := []string{SYSTEM_CONFIG}
+
+	if shared := os.Getenv(CONFIG_PATH_ENV); 0 < len(shared) {
+		for _, entry := range strings.Split(shared, string(os.PathListSeparator)) {
+			if 0 == len(entry) {
+				continue
+			} else if DirExists(entry) {
+				entry = filepath.Join(entry, CONFIG_NAME)
+			}
+
+			layers = append(layers, entry)
+		}
+	}
+
+	if root := UserConfigDir(); 0 < len(root) {
+		layers = append(layers, filepath.Join(root, CONFIG_NAME))
+	}
+
+	return layers
+}
+
+// the gospace directory within the user configuration root.
+// an empty string is returned if neither XDG_CONFIG_HOME nor HOME
+// are defined.
+func UserConfigDir() string {
+	if xdg := os.Getenv(XDG_CONFIG_ENV); 0 < len(xdg) {
+		return filepath.Join(xdg, "gospace")
+	} else if home := os.Getenv(HOME_ENV); 0 < len(home) {
+		return filepath.Join(home, ".config", "gospace")
+	}
+
+	return ""
+}
+
+// read and merge the user and system configuration files. missing
+// files are skipped, unreadable or invalid files cause an error.
+func LoadConfigLayers() (*Config, error) {
+	config := NewConfig()
+
+	for _, layer := range ConfigLayers() {
+		if false == PathExists(layer) {
+			LOG_CONFIG.T("configuration layer", layer, "does not exist")
+			continue
+		}
+
+		LOG_CONFIG.D("using configuration layer", layer)
+
+		if next, err := LoadConfig(layer); nil != err {
+			return nil, err
+		} else {
+			config = config.Merge(next)
+		}
+	}
+
+	return config, nil
+}
+
+func absolutePaths(paths []string, base string) {
+	for i, path := range paths {
+		if false == filepath.IsAbs(path) {
+			paths[i] = filepath.Join(base, path)
+		}
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if 0 < len(value) {
+			return value
+		}
+	}
+
+	return ""
+}
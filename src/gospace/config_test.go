@@ -0,0 +1,111 @@
+package gospace
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigMerge(t *testing.T) {
+	tests := []struct {
+		lower    *Config
+		upper    *Config
+		expected *Config
+	}{
+		{
+			&Config{Go: "1.5", Shell: "/bin/sh", Include: []string{"/a"}},
+			nil,
+			&Config{Go: "1.5", Shell: "/bin/sh", Include: []string{"/a"}},
+		},
+		{
+			&Config{Go: "1.5", Shell: "/bin/sh", DefaultShell: "/bin/bash"},
+			&Config{Go: "1.6"},
+			&Config{Go: "1.6", Shell: "/bin/sh", DefaultShell: "/bin/bash"},
+		},
+		{
+			&Config{Include: []string{"/a"}, Spaces: []string{"/s"}},
+			&Config{Include: []string{"/b"}, Spaces: []string{"/t"}},
+			&Config{Include: []string{"/a", "/b"}, Spaces: []string{"/s", "/t"}},
+		},
+		{
+			&Config{ShellArgs: []string{"--login"}},
+			&Config{ShellArgs: []string{}},
+			&Config{ShellArgs: []string{"--login"}},
+		},
+		{
+			&Config{ShellArgs: []string{"--login"}},
+			&Config{ShellArgs: []string{"-i"}},
+			&Config{ShellArgs: []string{"-i"}},
+		},
+		{
+			&Config{Env: map[string]string{"A": "1", "B": "2"}},
+			&Config{Env: map[string]string{"B": "3", "C": "4"}},
+			&Config{Env: map[string]string{"A": "1", "B": "3", "C": "4"}},
+		},
+	}
+
+	for _, test := range tests {
+		expected := NewConfig().Merge(test.expected)
+
+		if actual := test.lower.Merge(test.upper); fmt.Sprintf("%+v", *actual) != fmt.Sprintf("%+v", *expected) {
+			t.Errorf("merging %+v onto %+v\n got: %+v\nwant: %+v", test.upper, test.lower, *actual, *expected)
+		}
+	}
+}
+
+// write the configuration file, creating its directory
+func writeConfig(t *testing.T, file string, content string) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); nil != err {
+		t.Fatal(err)
+	} else if err := ioutil.WriteFile(file, []byte(content), 0644); nil != err {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfigLayers(t *testing.T) {
+	root, err := ioutil.TempDir("", "gospace-config")
+
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	system := SYSTEM_CONFIG
+	SYSTEM_CONFIG = filepath.Join(root, "etc", "config")
+	defer func() { SYSTEM_CONFIG = system }()
+
+	os.Setenv(CONFIG_PATH_ENV, filepath.Join(root, "shared")+string(os.PathListSeparator)+filepath.Join(root, "missing"))
+	os.Setenv(XDG_CONFIG_ENV, filepath.Join(root, "user"))
+	defer os.Unsetenv(CONFIG_PATH_ENV)
+	defer os.Unsetenv(XDG_CONFIG_ENV)
+
+	writeConfig(t, SYSTEM_CONFIG, `{"go": "1.5", "shell": "/bin/sh", "include": ["lib"], "env": {"A": "system", "B": "system"}}`)
+	writeConfig(t, filepath.Join(root, "shared", "config"), `{"go": "1.6", "env": {"B": "shared"}}`)
+	writeConfig(t, filepath.Join(root, "user", "gospace", "config"), `{"shell": "/bin/zsh", "include": ["/opt/lib"]}`)
+
+	config, err := LoadConfigLayers()
+
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	expected := NewConfig().Merge(&Config{
+		Go:      "1.6",
+		Shell:   "/bin/zsh",
+		Include: []string{filepath.Join(root, "etc", "lib"), "/opt/lib"},
+		Env:     map[string]string{"A": "system", "B": "shared"},
+	})
+
+	if fmt.Sprintf("%+v", *config) != fmt.Sprintf("%+v", *expected) {
+		t.Errorf("layered configuration\n got: %+v\nwant: %+v", *config, *expected)
+	}
+
+	writeConfig(t, filepath.Join(root, "user", "gospace", "config"), `{"shell": `)
+
+	if _, err = LoadConfigLayers(); nil == err {
+		t.Errorf("expected an invalid user configuration to fail")
+	}
+}
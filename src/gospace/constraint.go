@@ -0,0 +1,119 @@
+package gospace
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// comparison operators ordered by length to match >= before >
+	constraintOperators = []string{">=", "<=", "==", "!=", ">", "<", "="}
+)
+
+// a set of version requirements, e.g. >= 1.5, < 1.7.
+// all clauses have to be satisfied.
+type VersionConstraint struct {
+	expression string
+	clauses    []versionClause
+}
+
+type versionClause struct {
+	operator string
+	release  []int
+}
+
+// check if the release satisfies every clause. releases without
+// version information never match.
+func (c *VersionConstraint) Matches(release []int) bool {
+	if 0 == len(release) {
+		return false
+	}
+
+	for _, clause := range c.clauses {
+		if false == clause.matches(release) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *VersionConstraint) String() string {
+	return c.expression
+}
+
+func (v versionClause) matches(release []int) bool {
+	diff := compareRelease(release, v.release)
+
+	switch v.operator {
+	case ">=":
+		return 0 <= diff
+	case "<=":
+		return 0 >= diff
+	case ">":
+		return 0 < diff
+	case "<":
+		return 0 > diff
+	case "!=":
+		return 0 != diff
+	default:
+		return 0 == diff
+	}
+}
+
+// check if the expression looks like a version constraint instead
+// of a go installation name or directory. constraints start with a
+// comparison operator, optionally preceded by the word _go_.
+func IsVersionConstraint(expression string) bool {
+	trimmed := strings.TrimSpace(expression)
+	trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "go "))
+
+	for _, operator := range constraintOperators {
+		if strings.HasPrefix(trimmed, operator) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parse a comma separated list of comparisons. each comparison
+// consists of an operator (>=, <=, >, <, =, ==, !=) and a version.
+// the expression may start with the word _go_, e.g.
+// go >= 1.5, < 1.7
+func ParseVersionConstraint(expression string) (*VersionConstraint, error) {
+	trimmed := strings.TrimSpace(expression)
+	trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "go "))
+	constraint := &VersionConstraint{trimmed, []versionClause{}}
+
+	for _, part := range strings.Split(trimmed, ",") {
+		clause, err := parseVersionClause(strings.TrimSpace(part))
+
+		if nil != err {
+			return nil, fmt.Errorf("Invalid version constraint '%s': %s",
+				expression,
+				err.Error())
+		}
+
+		constraint.clauses = append(constraint.clauses, clause)
+	}
+
+	return constraint, nil
+}
+
+func parseVersionClause(value string) (versionClause, error) {
+	for _, operator := range constraintOperators {
+		if strings.HasPrefix(value, operator) {
+			version := strings.TrimSpace(strings.TrimPrefix(value, operator))
+			release := parseRelease(version)
+
+			if 0 == len(release) {
+				return versionClause{}, fmt.Errorf("no version in '%s'", value)
+			}
+
+			return versionClause{operator, release}, nil
+		}
+	}
+
+	return versionClause{}, fmt.Errorf("no operator in '%s'", value)
+}
@@ -0,0 +1,125 @@
+package gospace
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseRelease(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected []int
+	}{
+		{"1.8", []int{1, 8}},
+		{"go1.8", []int{1, 8}},
+		{"go1.10.3", []int{1, 10, 3}},
+		{"1.9rc2", []int{1, 9}},
+		{"go1.11beta1", []int{1, 11}},
+		{"1.x", []int{1}},
+		{"devel", []int{}},
+		{"", []int{}},
+	}
+
+	for _, test := range tests {
+		if actual := parseRelease(test.version); fmt.Sprint(actual) != fmt.Sprint(test.expected) {
+			t.Errorf("parseRelease(%q) = %v, want %v", test.version, actual, test.expected)
+		}
+	}
+}
+
+func TestCompareRelease(t *testing.T) {
+	tests := []struct {
+		a, b     []int
+		expected int
+	}{
+		{[]int{1, 8}, []int{1, 8}, 0},
+		{[]int{1, 8}, []int{1, 8, 0}, 0},
+		{[]int{1, 8}, []int{1, 9}, -1},
+		{[]int{1, 10}, []int{1, 9}, 1},
+		{[]int{1, 8, 1}, []int{1, 8}, 1},
+		{[]int{2}, []int{1, 20}, 1},
+		{[]int{}, []int{}, 0},
+	}
+
+	for _, test := range tests {
+		actual := compareRelease(test.a, test.b)
+
+		if (0 > actual) != (0 > test.expected) || (0 < actual) != (0 < test.expected) {
+			t.Errorf("compareRelease(%v, %v) = %d, want sign of %d", test.a, test.b, actual, test.expected)
+		}
+	}
+}
+
+func TestVersionConstraint(t *testing.T) {
+	tests := []struct {
+		expression string
+		release    []int
+		expected   bool
+	}{
+		{"go >= 1.5, < 1.7", []int{1, 5}, true},
+		{"go >= 1.5, < 1.7", []int{1, 6, 3}, true},
+		{"go >= 1.5, < 1.7", []int{1, 7}, false},
+		{"go >= 1.5, < 1.7", []int{1, 4, 9}, false},
+		{">=1.5,<1.7", []int{1, 6}, true},
+		{"> 1.8", []int{1, 8}, false},
+		{"> 1.8", []int{1, 8, 1}, true},
+		{"<= 1.8", []int{1, 8, 0}, true},
+		{"= 1.8", []int{1, 8}, true},
+		{"== 1.8", []int{1, 8, 1}, false},
+		{"!= 1.8", []int{1, 9}, true},
+		{"!= 1.8", []int{1, 8}, false},
+		{">= go1.9", []int{1, 10}, true},
+		{">= 1.0", []int{}, false},
+	}
+
+	for _, test := range tests {
+		constraint, err := ParseVersionConstraint(test.expression)
+
+		if nil != err {
+			t.Errorf("ParseVersionConstraint(%q) failed: %s", test.expression, err)
+		} else if actual := constraint.Matches(test.release); actual != test.expected {
+			t.Errorf("%q matches %v = %v, want %v", test.expression, test.release, actual, test.expected)
+		}
+	}
+}
+
+func TestParseVersionConstraintErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"go",
+		"1.8",
+		">=",
+		">= 1.5,",
+		">= 1.5, 1.7",
+		"~ 1.8",
+		">= devel",
+	}
+
+	for _, expression := range tests {
+		if constraint, err := ParseVersionConstraint(expression); nil == err {
+			t.Errorf("ParseVersionConstraint(%q) = %s, want an error", expression, constraint)
+		}
+	}
+}
+
+func TestIsVersionConstraint(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{">= 1.5", true},
+		{"go >= 1.5, < 1.7", true},
+		{" <1.7", true},
+		{"!= 1.8", true},
+		{"1.8", false},
+		{"go1.8", false},
+		{"/usr/local/go", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if actual := IsVersionConstraint(test.expression); actual != test.expected {
+			t.Errorf("IsVersionConstraint(%q) = %v, want %v", test.expression, actual, test.expected)
+		}
+	}
+}
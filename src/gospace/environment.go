@@ -0,0 +1,169 @@
+package gospace
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// the variable is not part of the base environment
+	ENV_ADDED = "added"
+	// the variable has a different value than in the base environment
+	ENV_CHANGED = "changed"
+	// the variable of the base environment is not passed on
+	ENV_REMOVED = "removed"
+)
+
+// ordered set of environment variables. modifications never affect
+// the environment of the gospace process itself.
+type Environment struct {
+	names  []string
+	values map[string]string
+}
+
+// a single environment variable difference
+type EnvChange struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// the value of the variable and whether it is defined at all
+func (e *Environment) Get(name string) (string, bool) {
+	value, ok := e.values[name]
+
+	return value, ok
+}
+
+// define the variable. new variables are added at the end.
+func (e *Environment) Set(name string, value string) {
+	if _, ok := e.values[name]; false == ok {
+		e.names = append(e.names, name)
+	}
+
+	e.values[name] = value
+}
+
+// remove the variable
+func (e *Environment) Unset(name string) {
+	if _, ok := e.values[name]; false == ok {
+		return
+	}
+
+	delete(e.values, name)
+
+	for i, known := range e.names {
+		if known == name {
+			e.names = append(e.names[:i], e.names[i+1:]...)
+			break
+		}
+	}
+}
+
+// insert the directories at the start of the path-list variable
+func (e *Environment) Prepend(name string, directories ...string) {
+	e.Set(name, joinPathList(directories, e.pathList(name)))
+}
+
+// add the directories at the end of the path-list variable
+func (e *Environment) Append(name string, directories ...string) {
+	e.Set(name, joinPathList(e.pathList(name), directories))
+}
+
+// the variable names in order of definition
+func (e *Environment) Names() []string {
+	return append([]string{}, e.names...)
+}
+
+// the variables as NAME=VALUE pairs, see os.Environ
+func (e *Environment) Environ() []string {
+	pairs := make([]string, 0, len(e.names))
+
+	for _, name := range e.names {
+		pairs = append(pairs, name+"="+e.values[name])
+	}
+
+	return pairs
+}
+
+// create an independent duplicate
+func (e *Environment) Copy() *Environment {
+	return NewEnvironment(e.Environ())
+}
+
+// compute the changes required to turn _base_ into this environment.
+// the changes are ordered by variable name.
+func (e *Environment) Diff(base *Environment) []*EnvChange {
+	names := base.Names()
+	changes := []*EnvChange{}
+
+	for _, name := range e.names {
+		if _, ok := base.values[name]; false == ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		previous, existed := base.values[name]
+		next, exists := e.values[name]
+
+		switch {
+		case existed && false == exists:
+			changes = append(changes, &EnvChange{name, ENV_REMOVED, previous, ""})
+		case false == existed && exists:
+			changes = append(changes, &EnvChange{name, ENV_ADDED, "", next})
+		case previous != next:
+			changes = append(changes, &EnvChange{name, ENV_CHANGED, previous, next})
+		}
+	}
+
+	return changes
+}
+
+func (e *Environment) pathList(name string) []string {
+	if value, ok := e.values[name]; ok && 0 < len(value) {
+		return strings.Split(value, string(os.PathListSeparator))
+	}
+
+	return []string{}
+}
+
+func (c *EnvChange) String() string {
+	switch c.Action {
+	case ENV_ADDED:
+		return "+ " + c.Name + "=" + c.New
+	case ENV_REMOVED:
+		return "- " + c.Name + " (was " + c.Old + ")"
+	default:
+		return "~ " + c.Name + "=" + c.New + " (was " + c.Old + ")"
+	}
+}
+
+// create an environment from NAME=VALUE pairs. later definitions of
+// the same variable replace earlier ones.
+func NewEnvironment(pairs []string) *Environment {
+	env := &Environment{[]string{}, map[string]string{}}
+
+	for _, pair := range pairs {
+		if variable := strings.SplitN(pair, "=", 2); 2 == len(variable) {
+			env.Set(variable[0], variable[1])
+		}
+	}
+
+	return env
+}
+
+// snapshot of the environment of the gospace process
+func CurrentEnvironment() *Environment {
+	return NewEnvironment(os.Environ())
+}
+
+func joinPathList(head []string, tail []string) string {
+	list := append(append([]string{}, head...), tail...)
+
+	return strings.Join(list, string(os.PathListSeparator))
+}
@@ -0,0 +1,93 @@
+package gospace
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnvironmentPathList(t *testing.T) {
+	tests := []struct {
+		base     []string
+		prepend  []string
+		append   []string
+		expected string
+	}{
+		{[]string{}, []string{"/ws/bin"}, []string{}, "/ws/bin"},
+		{[]string{"PATH="}, []string{"/ws/bin"}, []string{}, "/ws/bin"},
+		{[]string{"PATH=/bin"}, []string{"/ws/bin", "/go/bin"}, []string{}, "/ws/bin:/go/bin:/bin"},
+		{[]string{"PATH=/usr/bin:/bin"}, []string{}, []string{"/opt/bin"}, "/usr/bin:/bin:/opt/bin"},
+		{[]string{"PATH=/bin"}, []string{"/ws/bin"}, []string{"/opt/bin"}, "/ws/bin:/bin:/opt/bin"},
+		{[]string{"PATH=/bin"}, []string{}, []string{}, "/bin"},
+	}
+
+	for _, test := range tests {
+		env := NewEnvironment(test.base)
+
+		if 0 < len(test.prepend) {
+			env.Prepend("PATH", test.prepend...)
+		}
+
+		if 0 < len(test.append) {
+			env.Append("PATH", test.append...)
+		}
+
+		if actual, _ := env.Get("PATH"); actual != test.expected {
+			t.Errorf("PATH of %v = %q, want %q", test.base, actual, test.expected)
+		}
+	}
+}
+
+func TestEnvironmentSetUnset(t *testing.T) {
+	env := NewEnvironment([]string{"A=1", "B=2", "A=3", "INVALID"})
+
+	env.Set("C", "4")
+	env.Set("B", "5")
+	env.Unset("A")
+	env.Unset("UNKNOWN")
+
+	if actual := strings.Join(env.Environ(), " "); "B=5 C=4" != actual {
+		t.Errorf("expected B=5 C=4, got %s", actual)
+	}
+
+	if _, ok := env.Get("A"); ok {
+		t.Errorf("expected A to be removed")
+	}
+
+	copied := env.Copy()
+	copied.Set("B", "6")
+
+	if value, _ := env.Get("B"); "5" != value {
+		t.Errorf("modifying the copy changed the original to %s", value)
+	}
+}
+
+func TestEnvironmentDiff(t *testing.T) {
+	tests := []struct {
+		base     []string
+		env      []string
+		expected []string
+	}{
+		{[]string{"A=1"}, []string{"A=1"}, []string{}},
+		{[]string{}, []string{"A=1"}, []string{"+ A=1"}},
+		{[]string{"A=1"}, []string{}, []string{"- A (was 1)"}},
+		{[]string{"A=1"}, []string{"A=2"}, []string{"~ A=2 (was 1)"}},
+		{[]string{"A="}, []string{}, []string{"- A (was )"}},
+		{
+			[]string{"C=3", "B=2", "A=1"},
+			[]string{"D=4", "B=5", "A=1"},
+			[]string{"~ B=5 (was 2)", "- C (was 3)", "+ D=4"},
+		},
+	}
+
+	for _, test := range tests {
+		actual := []string{}
+
+		for _, change := range NewEnvironment(test.env).Diff(NewEnvironment(test.base)) {
+			actual = append(actual, change.String())
+		}
+
+		if strings.Join(actual, ", ") != strings.Join(test.expected, ", ") {
+			t.Errorf("diff of %v against %v = %v, want %v", test.env, test.base, actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package gospace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// exit codes of the gospace command. the values follow sysexits(3).
+// the exit status of the shell is passed on unchanged, hence it may
+// coincide with these codes.
+const (
+	// the command succeeded
+	EXIT_SUCCESS = 0
+	// any error without a dedicated exit code
+	EXIT_FAILURE = 1
+	// the commandline is invalid
+	EXIT_USAGE = 64
+	// several workspaces match the name equally well
+	EXIT_AMBIGUOUS_WORKSPACE = 65
+	// the workspace name or path could not be resolved
+	EXIT_WORKSPACE_NOT_FOUND = 66
+	// a required program, e.g. a go installation, is not available
+	EXIT_UNAVAILABLE = 69
+	// no usable shell binary was found
+	EXIT_SHELL_NOT_FOUND = EXIT_UNAVAILABLE
+	// the shell was found, but could not be started
+	EXIT_LAUNCH = 71
+	// a file or directory is not accessible
+	EXIT_PERMISSION = 77
+	// a configuration file is invalid
+	EXIT_INVALID_CONFIG = 78
+)
+
+// none of the shell candidates refers to an existing file
+type ErrShellNotFound struct {
+	// the shell paths and names which were looked up
+	Candidates []string
+}
+
+// none of the go installations matches the directory, name or
+// version
+type ErrSDKNotFound struct {
+	Query string
+	// summary of the installed versions
+	Installed string
+}
+
+// the go installation does not satisfy the configured version
+// constraint
+type ErrConstraintMismatch struct {
+	Constraint *VersionConstraint
+	// the rejected installation; nil if none of the installations
+	// satisfies the constraint
+	SDK *SDK
+	// summary of the installed versions
+	Installed string
+}
+
+// the workspace path could not be resolved
+type ErrWorkspaceNotFound struct {
+	// the value as provided by the user
+	Name string
+	// the locations which were checked
+	Searched []*Candidate
+	// existing workspace names resembling the name
+	Suggestions []string
+}
+
+// there is no workspace registered under the name
+type ErrUnknownWorkspace struct {
+	Name string
+	// registered names resembling the name
+	Suggestions []string
+}
+
+// the workspace name matches several directories equally well
+type ErrAmbiguousWorkspace struct {
+	Name       string
+	Candidates []string
+	// the locations which were checked, followed by the matches
+	Searched []*Candidate
+}
+
+// the file or directory exists, but is not accessible
+type ErrPermission struct {
+	Path string
+	Err  error
+}
+
+// the interactive selection was aborted by the user
+type ErrSelectionCancelled struct{}
+
+// the configuration file can not be read or parsed
+type ErrInvalidConfig struct {
+	File string
+	Err  error
+}
+
+func (e *ErrShellNotFound) Error() string {
+	if 0 == len(e.Candidates) {
+		return "Unable to find any suitable shell"
+	}
+
+	return fmt.Sprintf("Unable to find any suitable shell (tried: %s)", strings.Join(e.Candidates, ", "))
+}
+
+func (e *ErrSDKNotFound) Error() string {
+	return fmt.Sprintf("No go installation matches '%s' (installed: %s)", e.Query, e.Installed)
+}
+
+func (e *ErrConstraintMismatch) Error() string {
+	if nil != e.SDK {
+		return fmt.Sprintf("Go installation %s (%s) does not satisfy '%s'", e.SDK.Name, e.SDK.Version, e.Constraint)
+	}
+
+	return fmt.Sprintf("No go installation satisfies '%s' (installed: %s)", e.Constraint, e.Installed)
+}
+
+func (e *ErrWorkspaceNotFound) Error() string {
+	if PREVIOUS_GOSPACE == e.Name && 0 < len(e.Searched) {
+		return fmt.Sprintf("The previous workspace '%s' does not exist anymore", e.Searched[0].Path)
+	} else if PREVIOUS_GOSPACE == e.Name {
+		return "No previously launched workspace"
+	}
+
+	return fmt.Sprintf("No such directory '%s'%s", e.Name, DidYouMean(e.Suggestions))
+}
+
+func (e *ErrUnknownWorkspace) Error() string {
+	return fmt.Sprintf("Unknown workspace '%s'%s", e.Name, DidYouMean(e.Suggestions))
+}
+
+func (e *ErrAmbiguousWorkspace) Error() string {
+	return fmt.Sprintf("Ambiguous workspace '%s' (could be %s)", e.Name, strings.Join(e.Candidates, ", "))
+}
+
+func (e *ErrPermission) Error() string {
+	return fmt.Sprintf("Permission denied '%s'", e.Path)
+}
+
+func (e *ErrSelectionCancelled) Error() string {
+	return "No workspace selected"
+}
+
+func (e *ErrInvalidConfig) Error() string {
+	return fmt.Sprintf("Invalid configuration '%s': %s", e.File, e.Err.Error())
+}
+
+// the exit code of the command for the error. wrapped errors are
+// unwrapped, errors without a dedicated exit code yield EXIT_FAILURE.
+func ExitCode(err error) int {
+	var shell *ErrShellNotFound
+	var sdk *ErrSDKNotFound
+	var mismatch *ErrConstraintMismatch
+	var notFound *ErrWorkspaceNotFound
+	var unknown *ErrUnknownWorkspace
+	var ambiguous *ErrAmbiguousWorkspace
+	var permission *ErrPermission
+	var config *ErrInvalidConfig
+
+	switch {
+	case nil == err:
+		return EXIT_SUCCESS
+	case errors.As(err, &shell), errors.As(err, &sdk):
+		return EXIT_UNAVAILABLE
+	case errors.As(err, &notFound), errors.As(err, &unknown):
+		return EXIT_WORKSPACE_NOT_FOUND
+	case errors.As(err, &ambiguous):
+		return EXIT_AMBIGUOUS_WORKSPACE
+	case errors.As(err, &permission):
+		return EXIT_PERMISSION
+	case errors.As(err, &config), errors.As(err, &mismatch):
+		return EXIT_INVALID_CONFIG
+	default:
+		return EXIT_FAILURE
+	}
+}
+
+// convert errors caused by missing access privileges to ErrPermission.
+// other errors are returned unchanged.
+func permissionError(path string, err error) error {
+	if nil != err && os.IsPermission(err) {
+		return &ErrPermission{path, err}
+	}
+
+	return err
+}
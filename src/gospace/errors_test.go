@@ -0,0 +1,37 @@
+package gospace
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	constraint, _ := ParseVersionConstraint(">= 1.9")
+
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, EXIT_SUCCESS},
+		{errors.New("anything"), EXIT_FAILURE},
+		{&ErrShellNotFound{[]string{"zsh"}}, EXIT_UNAVAILABLE},
+		{&ErrSDKNotFound{"1.4", "go1.8"}, EXIT_UNAVAILABLE},
+		{&ErrConstraintMismatch{constraint, nil, "go1.8"}, EXIT_INVALID_CONFIG},
+		{&ErrConstraintMismatch{constraint, &SDK{"go1.8", "go1.8", "/usr/lib/go-1.8"}, ""}, EXIT_INVALID_CONFIG},
+		{&ErrWorkspaceNotFound{"proj", []*Candidate{}, []string{}}, EXIT_WORKSPACE_NOT_FOUND},
+		{&ErrUnknownWorkspace{"api", []string{}}, EXIT_WORKSPACE_NOT_FOUND},
+		{&ErrAmbiguousWorkspace{"git", []string{"/a", "/b"}, []*Candidate{}}, EXIT_AMBIGUOUS_WORKSPACE},
+		{&ErrPermission{"/root", errors.New("denied")}, EXIT_PERMISSION},
+		{&ErrInvalidConfig{"gospace.json", errors.New("syntax")}, EXIT_INVALID_CONFIG},
+		{&ErrSelectionCancelled{}, EXIT_FAILURE},
+		{fmt.Errorf("resolving: %w", &ErrWorkspaceNotFound{"proj", []*Candidate{}, []string{}}), EXIT_WORKSPACE_NOT_FOUND},
+		{fmt.Errorf("selecting: %w", &ErrSDKNotFound{"1.4", "go1.8"}), EXIT_UNAVAILABLE},
+	}
+
+	for _, test := range tests {
+		if actual := ExitCode(test.err); actual != test.expected {
+			t.Errorf("ExitCode(%v) = %d, want %d", test.err, actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package gospace
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// syntax of a shell family to define and remove environment variables
+type Dialect interface {
+	// statement to export the variable
+	Set(name string, value string) string
+	// statement to remove the variable
+	Unset(name string) string
+}
+
+// sh, bash, zsh, ksh and friends
+type posixDialect struct{}
+
+// the friendly interactive shell
+type fishDialect struct{}
+
+// csh and tcsh
+type cshDialect struct{}
+
+func (d posixDialect) Set(name string, value string) string {
+	return fmt.Sprintf("export %s=%s;", name, quotePosix(value))
+}
+
+func (d posixDialect) Unset(name string) string {
+	return fmt.Sprintf("unset %s;", name)
+}
+
+func (d fishDialect) Set(name string, value string) string {
+	// fish treats PATH as a list; a single colon separated string
+	// would be a single (invalid) entry in older releases
+	if OS_ENV == name {
+		fragments := []string{}
+
+		for _, fragment := range filepath.SplitList(value) {
+			fragments = append(fragments, quoteFish(fragment))
+		}
+
+		return fmt.Sprintf("set -gx %s %s;", name, strings.Join(fragments, " "))
+	}
+
+	return fmt.Sprintf("set -gx %s %s;", name, quoteFish(value))
+}
+
+func (d fishDialect) Unset(name string) string {
+	return fmt.Sprintf("set -e %s;", name)
+}
+
+func (d cshDialect) Set(name string, value string) string {
+	return fmt.Sprintf("setenv %s %s;", name, quoteCsh(value))
+}
+
+func (d cshDialect) Unset(name string) string {
+	return fmt.Sprintf("unsetenv %s;", name)
+}
+
+// find the dialect of the shell. the name may also be the path of
+// the shell binary.
+func LookupDialect(shell string) (Dialect, error) {
+	switch filepath.Base(shell) {
+	case "sh", "bash", "zsh", "ksh", "mksh", "dash", "ash":
+		return posixDialect{}, nil
+	case "fish":
+		return fishDialect{}, nil
+	case "csh", "tcsh":
+		return cshDialect{}, nil
+	default:
+		return nil, fmt.Errorf("Unsupported shell dialect '%s'", shell)
+	}
+}
+
+// write the statements to define the workspace environment to the
+// writer. the output can be evaluated by a shell of the dialect.
+// variables which would be removed from the environment are unset.
+func (w *Workspace) Export(out io.Writer, dialect Dialect) error {
+	return w.export(out, dialect, CurrentEnvironment())
+}
+
+// same as Export, but based on the given environment instead of the
+// one of the process
+func (w *Workspace) export(out io.Writer, dialect Dialect, base *Environment) error {
+	env := w.Environment(base)
+	names := append([]string{OS_ENV, WS_ENV, PKG_ENV, GOROOT_ENV}, w.envNames()...)
+	removed := map[string]bool{}
+
+	for _, change := range env.Diff(base) {
+		removed[change.Name] = ENV_REMOVED == change.Action
+	}
+
+	for _, name := range names {
+		var statement string
+
+		if value, ok := env.Get(name); ok {
+			statement = dialect.Set(name, value)
+		} else if removed[name] {
+			statement = dialect.Unset(name)
+		} else {
+			continue
+		}
+
+		if _, err := io.WriteString(out, statement+"\n"); nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func quotePosix(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+func quoteFish(value string) string {
+	escaped := strings.Replace(value, `\`, `\\`, -1)
+	escaped = strings.Replace(escaped, "'", `\'`, -1)
+
+	return "'" + escaped + "'"
+}
+
+func quoteCsh(value string) string {
+	escaped := strings.Replace(value, "'", `'\''`, -1)
+	// history expansion applies within single quotes as well
+	escaped = strings.Replace(escaped, "!", `\!`, -1)
+	// newlines within quotes have to be escaped
+	escaped = strings.Replace(escaped, "\n", "\\\n", -1)
+
+	return "'" + escaped + "'"
+}
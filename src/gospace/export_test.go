@@ -0,0 +1,107 @@
+package gospace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		posix string
+		fish  string
+		csh   string
+	}{
+		{"/usr/bin", `'/usr/bin'`, `'/usr/bin'`, `'/usr/bin'`},
+		{"", `''`, `''`, `''`},
+		{"it's", `'it'\''s'`, `'it\'s'`, `'it'\''s'`},
+		{`a\b`, `'a\b'`, `'a\\b'`, `'a\b'`},
+		{"$HOME `id`", "'$HOME `id`'", "'$HOME `id`'", "'$HOME `id`'"},
+		{"wow!", `'wow!'`, `'wow!'`, `'wow\!'`},
+		{"two\nlines", "'two\nlines'", "'two\nlines'", "'two\\\nlines'"},
+	}
+
+	for _, test := range tests {
+		if actual := quotePosix(test.value); actual != test.posix {
+			t.Errorf("quotePosix(%q) = %s, want %s", test.value, actual, test.posix)
+		}
+
+		if actual := quoteFish(test.value); actual != test.fish {
+			t.Errorf("quoteFish(%q) = %s, want %s", test.value, actual, test.fish)
+		}
+
+		if actual := quoteCsh(test.value); actual != test.csh {
+			t.Errorf("quoteCsh(%q) = %s, want %s", test.value, actual, test.csh)
+		}
+	}
+}
+
+func TestLookupDialect(t *testing.T) {
+	tests := []struct {
+		shell    string
+		expected string
+	}{
+		{"bash", "export A='b';"},
+		{"/bin/zsh", "export A='b';"},
+		{"/usr/bin/fish", "set -gx A 'b';"},
+		{"tcsh", "setenv A 'b';"},
+		{"csh", "setenv A 'b';"},
+		{"nu", ""},
+	}
+
+	for _, test := range tests {
+		dialect, err := LookupDialect(test.shell)
+
+		if 0 == len(test.expected) {
+			if nil == err {
+				t.Errorf("LookupDialect(%q) should fail", test.shell)
+			}
+		} else if nil != err {
+			t.Errorf("LookupDialect(%q) failed: %s", test.shell, err)
+		} else if actual := dialect.Set("A", "b"); actual != test.expected {
+			t.Errorf("%s dialect yields %s, want %s", test.shell, actual, test.expected)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	ws := &Workspace{Root: "/ws", GoPath: []string{}, OsPath: []string{}, Env: map[string]string{"EDITOR": "vi"}}
+
+	tests := []struct {
+		base     []string
+		dialect  Dialect
+		expected []string
+	}{
+		{
+			[]string{"PATH=/bin"},
+			posixDialect{},
+			[]string{"export PATH='/ws/bin:/bin';", "export GOPATH='/ws';", "export GOBIN='/ws/bin';", "export EDITOR='vi';"},
+		},
+		{
+			[]string{"PATH=/bin", "GOROOT=/nonexistent/go"},
+			posixDialect{},
+			[]string{"export PATH='/ws/bin:/bin';", "export GOPATH='/ws';", "export GOBIN='/ws/bin';", "unset GOROOT;", "export EDITOR='vi';"},
+		},
+		{
+			[]string{"PATH=/usr/bin:/bin", "GOROOT=/nonexistent/go"},
+			fishDialect{},
+			[]string{"set -gx PATH '/ws/bin' '/usr/bin' '/bin';", "set -gx GOPATH '/ws';", "set -gx GOBIN '/ws/bin';", "set -e GOROOT;", "set -gx EDITOR 'vi';"},
+		},
+		{
+			[]string{"PATH=/bin"},
+			cshDialect{},
+			[]string{"setenv PATH '/ws/bin:/bin';", "setenv GOPATH '/ws';", "setenv GOBIN '/ws/bin';", "setenv EDITOR 'vi';"},
+		},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+
+		if err := ws.export(&out, test.dialect, NewEnvironment(test.base)); nil != err {
+			t.Errorf("export based on %v failed: %s", test.base, err)
+		} else if expected := strings.Join(test.expected, "\n") + "\n"; out.String() != expected {
+			t.Errorf("export based on %v\n got: %s\nwant: %s", test.base, out.String(), expected)
+		}
+	}
+}
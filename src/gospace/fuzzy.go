@@ -0,0 +1,278 @@
+package gospace
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// the quality of a name component match, lower values are better
+const (
+	// the names are identical
+	MATCH_EXACT = iota
+	// the names only differ in case
+	MATCH_CASE
+	// the name starts with the query
+	MATCH_PREFIX
+	// the name contains the query
+	MATCH_SUBSTRING
+	// the name contains the characters of the query in order
+	MATCH_SUBSEQUENCE
+	// the name does not match the query
+	MATCH_NONE
+)
+
+// a directory matching a partial workspace name
+type fuzzyMatch struct {
+	// the path relative to the lookup directory
+	rel string
+	// the absolute path
+	path string
+	// the resolution source providing the lookup directory
+	source string
+	// the sum of the component ranks
+	rank int
+}
+
+// sort interface ordering by rank, then path
+type matchesByRank []*fuzzyMatch
+
+func (m matchesByRank) Len() int      { return len(m) }
+func (m matchesByRank) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+
+func (m matchesByRank) Less(i, j int) bool {
+	if m[i].rank == m[j].rank {
+		return m[i].path < m[j].path
+	}
+
+	return m[i].rank < m[j].rank
+}
+
+// rank how well the name matches the query, see the MATCH_* constants.
+// all but the exact match ignore the case.
+func MatchRank(query string, name string) int {
+	lowerQuery := strings.ToLower(query)
+	lowerName := strings.ToLower(name)
+
+	switch {
+	case query == name:
+		return MATCH_EXACT
+	case lowerQuery == lowerName:
+		return MATCH_CASE
+	case strings.HasPrefix(lowerName, lowerQuery):
+		return MATCH_PREFIX
+	case strings.Contains(lowerName, lowerQuery):
+		return MATCH_SUBSTRING
+	case isSubsequence(lowerQuery, lowerName):
+		return MATCH_SUBSEQUENCE
+	default:
+		return MATCH_NONE
+	}
+}
+
+// the best matches of the partial workspace name within the lookup
+// directories of the resolution sources, e.g. _proj_ for _myproject_
+// or _gh/foo_ for _github.com/foo_. each component of the name is
+// matched against the directory entries of the corresponding level.
+// a relative path found in several lookup directories is reported
+// once, for the source with the highest precedence. the names of the
+// registered workspaces and the trailing components of the launched
+// workspaces are matched the same way.
+// among equally good matches, the one with the highest frecency wins.
+// the result is empty if nothing matches and contains several entries
+// if they match equally well.
+func matchGospaces(dir string, order []string, registry *WorkspaceRegistry, history *History) []*fuzzyMatch {
+	matches := []*fuzzyMatch{}
+	known := map[string]bool{}
+	components := nameComponents(dir)
+
+	if 0 == len(components) {
+		return matches
+	}
+
+	for _, source := range order {
+		if SOURCE_PWD == source {
+			continue
+		} else if SOURCE_REGISTRY == source && 1 == len(components) {
+			for _, ws := range registry.Workspaces {
+				if rank := MatchRank(components[0], ws.Name); MATCH_NONE != rank && false == known[ws.Name] && DirExists(ws.Path) {
+					known[ws.Name] = true
+					matches = append(matches, &fuzzyMatch{ws.Name, ws.Path, source, rank})
+				}
+			}
+		} else if SOURCE_HISTORY == source {
+			for _, match := range matchHistory(history, components) {
+				if false == known[match.rel] {
+					known[match.rel] = true
+					matches = append(matches, match)
+				}
+			}
+		}
+
+		for _, root := range lookupDirectories(source) {
+			if 0 == len(root) {
+				continue
+			}
+
+			for _, match := range matchComponents(root, components) {
+				if known[match.rel] {
+					continue
+				}
+
+				known[match.rel] = true
+				match.path = filepath.Join(root, match.rel)
+				match.source = source
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	sort.Sort(matchesByRank(matches))
+
+	for i, match := range matches {
+		if match.rank != matches[0].rank {
+			matches = matches[:i]
+			break
+		}
+	}
+
+	return preferFrequent(matches, history)
+}
+
+// match the components against the trailing path components of each
+// launched workspace which still exists
+func matchHistory(history *History, components []string) []*fuzzyMatch {
+	matches := []*fuzzyMatch{}
+
+	for _, entry := range history.Ranked(time.Now()) {
+		names := strings.Split(entry.Path, string(filepath.Separator))
+		matched := true
+		rank := 0
+
+		if len(names) <= len(components) || false == DirExists(entry.Path) {
+			continue
+		}
+
+		names = names[len(names)-len(components):]
+
+		for i, component := range components {
+			componentRank := MatchRank(component, names[i])
+
+			if MATCH_NONE == componentRank {
+				matched = false
+				break
+			}
+
+			rank += componentRank
+		}
+
+		if matched {
+			matches = append(matches, &fuzzyMatch{filepath.Join(names...), entry.Path, SOURCE_HISTORY, rank})
+		}
+	}
+
+	return matches
+}
+
+// reduce equally good matches to the one with the highest frecency.
+// the matches are returned unchanged if no single match is more
+// frequently used than the others.
+func preferFrequent(matches []*fuzzyMatch, history *History) []*fuzzyMatch {
+	var best *fuzzyMatch
+
+	now := time.Now()
+	highest := 0.0
+	ties := 0
+
+	if 2 > len(matches) {
+		return matches
+	}
+
+	for _, match := range matches {
+		frecency := 0.0
+
+		if entry := history.Find(match.path); nil != entry {
+			frecency = entry.Frecency(now)
+		}
+
+		if frecency > highest {
+			best, highest, ties = match, frecency, 1
+		} else if frecency == highest {
+			ties++
+		}
+	}
+
+	if nil != best && 1 == ties {
+		LOG_RESOLVE.D("preferring", best.path, "with frecency", highest)
+		return []*fuzzyMatch{best}
+	}
+
+	return matches
+}
+
+// match the first component against the directories within the root
+// and the remaining components against their children
+func matchComponents(root string, components []string) []*fuzzyMatch {
+	matches := []*fuzzyMatch{}
+	entries, err := ioutil.ReadDir(root)
+
+	if nil != err {
+		LOG_RESOLVE.T("unable to list lookup directory", root, err)
+		return matches
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		rank := MatchRank(components[0], name)
+
+		if MATCH_NONE == rank || strings.HasPrefix(name, ".") || false == DirExists(filepath.Join(root, name)) {
+			continue
+		} else if 1 == len(components) {
+			matches = append(matches, &fuzzyMatch{name, "", "", rank})
+			continue
+		}
+
+		for _, child := range matchComponents(filepath.Join(root, name), components[1:]) {
+			child.rel = filepath.Join(name, child.rel)
+			child.rank += rank
+			matches = append(matches, child)
+		}
+	}
+
+	return matches
+}
+
+// the components of a relative workspace name. absolute names and
+// names referring to parent directories are not matched.
+func nameComponents(dir string) []string {
+	clean := filepath.Clean(dir)
+
+	if filepath.IsAbs(clean) || "." == clean {
+		return []string{}
+	}
+
+	components := strings.Split(clean, string(filepath.Separator))
+
+	for _, component := range components {
+		if ".." == component {
+			return []string{}
+		}
+	}
+
+	return components
+}
+
+// check if the characters of the query appear in the value in order
+func isSubsequence(query string, value string) bool {
+	remaining := []rune(query)
+
+	for _, char := range value {
+		if 0 < len(remaining) && char == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+
+	return 0 == len(remaining)
+}
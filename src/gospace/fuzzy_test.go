@@ -0,0 +1,154 @@
+package gospace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchRank(t *testing.T) {
+	tests := []struct {
+		query    string
+		name     string
+		expected int
+	}{
+		{"myproject", "myproject", MATCH_EXACT},
+		{"MyProject", "myproject", MATCH_CASE},
+		{"my", "myproject", MATCH_PREFIX},
+		{"MY", "myproject", MATCH_PREFIX},
+		{"proj", "myproject", MATCH_SUBSTRING},
+		{"gh", "github.com", MATCH_SUBSEQUENCE},
+		{"mpj", "myproject", MATCH_SUBSEQUENCE},
+		{"jp", "myproject", MATCH_NONE},
+		{"myprojects", "myproject", MATCH_NONE},
+		{"gl", "github.com", MATCH_NONE},
+	}
+
+	for _, test := range tests {
+		if actual := MatchRank(test.query, test.name); actual != test.expected {
+			t.Errorf("MatchRank(%q, %q) = %d, want %d", test.query, test.name, actual, test.expected)
+		}
+	}
+}
+
+// create the directories within a temporary lookup directory, which
+// is exported as GOSPACES
+func lookupFixture(t *testing.T, dirs ...string) string {
+	root, err := ioutil.TempDir("", "gospace-fuzzy")
+
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); nil != err {
+			t.Fatal(err)
+		}
+	}
+
+	os.Setenv(SPACES_ENV, root)
+
+	return root
+}
+
+func TestMatchGospaces(t *testing.T) {
+	root := lookupFixture(t, "myproject", "tools/cmd", "github.com/foo", "gitlab.com/bar", ".hidden")
+	defer os.RemoveAll(root)
+	defer os.Unsetenv(SPACES_ENV)
+
+	registry := &WorkspaceRegistry{"", []*NamedWorkspace{}}
+	history := &History{"", []*HistoryEntry{}}
+
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{"myproj", []string{"myproject"}},
+		{"mypro", []string{"myproject"}},
+		{"proj", []string{"myproject"}},
+		{"MYPROJECT", []string{"myproject"}},
+		{"git", []string{"github.com", "gitlab.com"}},
+		{"gh/foo", []string{"github.com/foo"}},
+		{"g/f", []string{"github.com/foo"}},
+		{"git/ba", []string{"gitlab.com/bar"}},
+		{"to/c", []string{"tools/cmd"}},
+		{"hidden", []string{}},
+		{"nothing", []string{}},
+		{"/myproject", []string{}},
+		{"../myproject", []string{}},
+	}
+
+	for _, test := range tests {
+		matches := matchGospaces(test.name, []string{SOURCE_PWD, SPACES_ENV}, registry, history)
+		actual := []string{}
+
+		for _, match := range matches {
+			actual = append(actual, match.rel)
+		}
+
+		if strings.Join(actual, " ") != strings.Join(test.expected, " ") {
+			t.Errorf("matchGospaces(%q) = %v, want %v", test.name, actual, test.expected)
+		}
+	}
+}
+
+func TestMatchGospacesRegistry(t *testing.T) {
+	root := lookupFixture(t, "myproject", "work/api")
+	defer os.RemoveAll(root)
+	defer os.Unsetenv(SPACES_ENV)
+
+	registry := &WorkspaceRegistry{"", []*NamedWorkspace{
+		{Name: "backend", Path: filepath.Join(root, "work/api")},
+		{Name: "missing", Path: filepath.Join(root, "missing")},
+	}}
+	history := &History{"", []*HistoryEntry{}}
+	order := []string{SOURCE_REGISTRY, SPACES_ENV}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"back", filepath.Join(root, "work/api")},
+		{"myp", filepath.Join(root, "myproject")},
+		{"miss", ""},
+	}
+
+	for _, test := range tests {
+		actual := ""
+
+		if matches := matchGospaces(test.name, order, registry, history); 1 == len(matches) {
+			actual = matches[0].path
+		}
+
+		if actual != test.expected {
+			t.Errorf("matchGospaces(%q) = %q, want %q", test.name, actual, test.expected)
+		}
+	}
+}
+
+func TestMatchGospacesFrecency(t *testing.T) {
+	root := lookupFixture(t, "myproject", "oldproject")
+	defer os.RemoveAll(root)
+	defer os.Unsetenv(SPACES_ENV)
+
+	registry := &WorkspaceRegistry{"", []*NamedWorkspace{}}
+	history := &History{"", []*HistoryEntry{
+		{filepath.Join(root, "oldproject"), 3, time.Now()},
+		{filepath.Join(root, "myproject"), 1, time.Now()},
+	}}
+
+	matches := matchGospaces("project", []string{SPACES_ENV}, registry, history)
+
+	if 1 != len(matches) || filepath.Join(root, "oldproject") != matches[0].path {
+		t.Errorf("expected the most frequently used oldproject, got %v", matches)
+	}
+
+	history.Entries[1].Count = 3
+
+	if matches = matchGospaces("project", []string{SPACES_ENV}, registry, history); 2 != len(matches) {
+		t.Errorf("expected an ambiguous match for equal frecencies, got %v", matches)
+	}
+}
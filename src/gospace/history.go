@@ -0,0 +1,206 @@
+package gospace
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// name of the file recording the launched workspaces
+	HISTORY_NAME string = "history"
+	// environment variable pointing to the user state root
+	XDG_STATE_ENV string = "XDG_STATE_HOME"
+	// workspace name referring to the most recently launched workspace
+	PREVIOUS_GOSPACE string = "-"
+	// resolution source of the launched workspaces
+	SOURCE_HISTORY = "history"
+	// maximum number of workspaces kept in the history
+	HISTORY_LIMIT = 200
+)
+
+// a workspace launched before
+type HistoryEntry struct {
+	// the absolute workspace root
+	Path string `json:"path"`
+	// the number of launches
+	Count int `json:"count"`
+	// the time of the latest launch
+	LastUsed time.Time `json:"last_used"`
+}
+
+// the launched workspaces, stored as JSON array in the state file
+type History struct {
+	// the state file, empty if the history is not persisted
+	File    string
+	Entries []*HistoryEntry
+}
+
+// sort interface ordering by frecency, then most recent use
+type entriesByFrecency struct {
+	entries []*HistoryEntry
+	now     time.Time
+}
+
+func (e entriesByFrecency) Len() int      { return len(e.entries) }
+func (e entriesByFrecency) Swap(i, j int) { e.entries[i], e.entries[j] = e.entries[j], e.entries[i] }
+
+func (e entriesByFrecency) Less(i, j int) bool {
+	a := e.entries[i].Frecency(e.now)
+	b := e.entries[j].Frecency(e.now)
+
+	if a == b {
+		return e.entries[i].LastUsed.After(e.entries[j].LastUsed)
+	}
+
+	return a > b
+}
+
+// the launch count weighted by the age of the latest launch, as known
+// from z: launches within the last hour count four times, within the
+// last day twice, within the last week half and older ones a quarter.
+func (e *HistoryEntry) Frecency(now time.Time) float64 {
+	age := now.Sub(e.LastUsed)
+
+	switch {
+	case age < time.Hour:
+		return float64(e.Count) * 4
+	case age < 24*time.Hour:
+		return float64(e.Count) * 2
+	case age < 7*24*time.Hour:
+		return float64(e.Count) / 2
+	default:
+		return float64(e.Count) / 4
+	}
+}
+
+// the entries in descending order of frecency
+func (h *History) Ranked(now time.Time) []*HistoryEntry {
+	ranked := append([]*HistoryEntry{}, h.Entries...)
+
+	sort.Sort(entriesByFrecency{ranked, now})
+
+	return ranked
+}
+
+// the most recently launched workspace or nil if the history is empty
+func (h *History) Last() *HistoryEntry {
+	var last *HistoryEntry
+
+	for _, entry := range h.Entries {
+		if nil == last || entry.LastUsed.After(last.LastUsed) {
+			last = entry
+		}
+	}
+
+	return last
+}
+
+// the entry of the workspace root or nil if it was never launched
+func (h *History) Find(path string) *HistoryEntry {
+	for _, entry := range h.Entries {
+		if entry.Path == path {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// count a launch of the workspace root. the entries with the lowest
+// frecency are dropped once the history exceeds HISTORY_LIMIT.
+func (h *History) Record(path string, now time.Time) {
+	if entry := h.Find(path); nil != entry {
+		entry.Count++
+		entry.LastUsed = now
+	} else {
+		h.Entries = append(h.Entries, &HistoryEntry{path, 1, now})
+	}
+
+	if len(h.Entries) > HISTORY_LIMIT {
+		h.Entries = h.Ranked(now)[:HISTORY_LIMIT]
+	}
+}
+
+// the entries whose trailing path components equal the relative
+// workspace name, in descending order of frecency
+func (h *History) Lookup(dir string, now time.Time) []*HistoryEntry {
+	matches := []*HistoryEntry{}
+	suffix := string(filepath.Separator) + filepath.Clean(dir)
+
+	if filepath.IsAbs(dir) {
+		return matches
+	}
+
+	for _, entry := range h.Ranked(now) {
+		if strings.HasSuffix(entry.Path, suffix) {
+			matches = append(matches, entry)
+		}
+	}
+
+	return matches
+}
+
+// write the history to its state file. the directory is created if
+// necessary and the file is replaced atomically.
+func (h *History) Save() error {
+	if 0 == len(h.File) {
+		return nil
+	}
+
+	if data, err := json.MarshalIndent(h.Entries, "", "  "); nil != err {
+		return err
+	} else {
+		return replaceFile(h.File, append(data, '\n'))
+	}
+}
+
+// read the history from the state file. a missing file yields an
+// empty history.
+func LoadHistory(file string) (*History, error) {
+	history := &History{file, []*HistoryEntry{}}
+
+	if 0 == len(file) || false == PathExists(file) {
+		return history, nil
+	}
+
+	LOG_WORKSPACE.T("reading history", file)
+
+	if data, err := ioutil.ReadFile(file); nil != err {
+		return history, permissionError(file, err)
+	} else if err = json.Unmarshal(data, &history.Entries); nil != err {
+		return history, err
+	}
+
+	return history, nil
+}
+
+// the history of the user. unreadable state files are reported and
+// treated as an empty history, since they must not prevent launching
+// a workspace.
+func UserHistory() *History {
+	history, err := LoadHistory(HistoryFile())
+
+	if nil != err {
+		LOG_WORKSPACE.W("ignoring history", history.File, err)
+		history.Entries = []*HistoryEntry{}
+	}
+
+	return history
+}
+
+// the history file within the user state root. an empty string is
+// returned if neither XDG_STATE_HOME nor HOME are defined.
+func HistoryFile() string {
+	if xdg := os.Getenv(XDG_STATE_ENV); 0 < len(xdg) {
+		return filepath.Join(xdg, "gospace", HISTORY_NAME)
+	} else if home := os.Getenv(HOME_ENV); 0 < len(home) {
+		return filepath.Join(home, ".local", "state", "gospace", HISTORY_NAME)
+	}
+
+	return ""
+}
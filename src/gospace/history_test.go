@@ -0,0 +1,163 @@
+package gospace
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryFrecency(t *testing.T) {
+	now := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		count    int
+		age      time.Duration
+		expected float64
+	}{
+		{1, 0, 4},
+		{3, 59 * time.Minute, 12},
+		{3, time.Hour, 6},
+		{3, 23 * time.Hour, 6},
+		{3, 24 * time.Hour, 1.5},
+		{4, 6 * 24 * time.Hour, 2},
+		{4, 7 * 24 * time.Hour, 1},
+		{4, 365 * 24 * time.Hour, 1},
+	}
+
+	for _, test := range tests {
+		entry := &HistoryEntry{"/ws", test.count, now.Add(-test.age)}
+
+		if actual := entry.Frecency(now); actual != test.expected {
+			t.Errorf("frecency of %d launches %s ago = %v, want %v", test.count, test.age, actual, test.expected)
+		}
+	}
+}
+
+// the paths of the entries, separated by spaces
+func historyPaths(entries []*HistoryEntry) string {
+	paths := []string{}
+
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+
+	return strings.Join(paths, " ")
+}
+
+func TestHistoryRanked(t *testing.T) {
+	now := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+	history := &History{"", []*HistoryEntry{
+		{"/old/often", 20, now.Add(-30 * 24 * time.Hour)},
+		{"/recent/once", 1, now.Add(-time.Minute)},
+		{"/yesterday", 2, now.Add(-2 * time.Hour)},
+		{"/tie/older", 1, now.Add(-3 * time.Hour)},
+		{"/tie/newer", 1, now.Add(-2 * time.Hour)},
+	}}
+
+	expected := "/old/often /recent/once /yesterday /tie/newer /tie/older"
+
+	if actual := historyPaths(history.Ranked(now)); actual != expected {
+		t.Errorf("ranked history = %s, want %s", actual, expected)
+	}
+
+	if last := history.Last(); "/recent/once" != last.Path {
+		t.Errorf("expected /recent/once to be the last entry, got %s", last.Path)
+	}
+
+	// a fortnight later the long unused workspace still leads, yet the
+	// recent ones lost their bonus
+	later := now.Add(14 * 24 * time.Hour)
+
+	if actual := historyPaths(history.Ranked(later)); "/old/often /yesterday /recent/once /tie/newer /tie/older" != actual {
+		t.Errorf("ranked history after two weeks = %s", actual)
+	}
+}
+
+func TestHistoryRecord(t *testing.T) {
+	now := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+	history := &History{"", []*HistoryEntry{}}
+
+	history.Record("/a", now)
+	history.Record("/b", now.Add(time.Minute))
+	history.Record("/a", now.Add(2*time.Minute))
+
+	if actual := fmt.Sprint(len(history.Entries)); "2" != actual {
+		t.Fatalf("expected 2 entries, got %s", actual)
+	}
+
+	if entry := history.Find("/a"); 2 != entry.Count || false == entry.LastUsed.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	for i := 0; i < HISTORY_LIMIT; i++ {
+		history.Record(fmt.Sprintf("/many/%d", i), now.Add(time.Hour))
+	}
+
+	if HISTORY_LIMIT != len(history.Entries) {
+		t.Errorf("expected the history to be limited to %d entries, got %d", HISTORY_LIMIT, len(history.Entries))
+	}
+
+	if nil == history.Find("/a") {
+		t.Errorf("expected the most frecent entry to be kept")
+	}
+}
+
+func TestHistoryLookup(t *testing.T) {
+	now := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+	history := &History{"", []*HistoryEntry{
+		{"/home/a/src/proj", 1, now},
+		{"/home/b/proj", 5, now},
+		{"/home/a/src/myproj", 9, now},
+	}}
+
+	tests := []struct {
+		dir      string
+		expected string
+	}{
+		{"proj", "/home/b/proj /home/a/src/proj"},
+		{"src/proj", "/home/a/src/proj"},
+		{"src/proj/", "/home/a/src/proj"},
+		{"/home/b/proj", ""},
+		{"other", ""},
+	}
+
+	for _, test := range tests {
+		if actual := historyPaths(history.Lookup(test.dir, now)); actual != test.expected {
+			t.Errorf("lookup of %s = %q, want %q", test.dir, actual, test.expected)
+		}
+	}
+}
+
+func TestHistorySaveLoad(t *testing.T) {
+	root, err := ioutil.TempDir("", "gospace-history")
+
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	file := filepath.Join(root, "gospace", HISTORY_NAME)
+	now := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if history, err := LoadHistory(file); nil != err || 0 != len(history.Entries) {
+		t.Fatalf("expected an empty history, got %v (%v)", history.Entries, err)
+	}
+
+	history := &History{file, []*HistoryEntry{}}
+	history.Record("/ws", now)
+
+	if err = history.Save(); nil != err {
+		t.Fatal(err)
+	}
+
+	if loaded, err := LoadHistory(file); nil != err {
+		t.Fatal(err)
+	} else if entry := loaded.Find("/ws"); nil == entry || 1 != entry.Count || false == entry.LastUsed.Equal(now) {
+		t.Errorf("unexpected entries %v", loaded.Entries)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package gospace
+
+import (
+	"os/exec"
+)
+
+// run the command and wait for it to exit. signals are not forwarded
+// on this platform.
+func supervise(cmd *exec.Cmd) error {
+	return cmd.Run()
+}
+
+// the permission to execute a file is not checked on this platform
+func executable(path string) error {
+	return nil
+}
@@ -0,0 +1,102 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package gospace
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	// signals passed on to the process group of the shell
+	FORWARD_SIGNALS = []os.Signal{
+		syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGHUP,
+		syscall.SIGWINCH,
+	}
+)
+
+// run the command in its own process group and wait for it to exit.
+// if gospace owns the terminal, the process group of the command
+// becomes the foreground process group until the command exits.
+// the forwarded signals are sent to the process group of the command.
+func supervise(cmd *exec.Cmd) error {
+	var signals chan os.Signal = make(chan os.Signal, len(FORWARD_SIGNALS))
+	var done chan bool = make(chan bool)
+	var interactive bool = ownsTerminal(os.Stdin)
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if interactive {
+		LOG_SHELL.T("handing the terminal over to the shell")
+		// Ctty refers to the file descriptor in the child process
+		cmd.SysProcAttr.Foreground = true
+		cmd.SysProcAttr.Ctty = 0
+	}
+
+	signal.Notify(signals, FORWARD_SIGNALS...)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); nil != err {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				LOG_SHELL.D("forwarding", sig, "to the shell")
+				syscall.Kill(-cmd.Process.Pid, sig.(syscall.Signal))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+	close(done)
+
+	if interactive {
+		reclaimTerminal(os.Stdin)
+	}
+
+	return err
+}
+
+// check if the file is a terminal and the process group of gospace
+// is its foreground process group.
+func ownsTerminal(file *os.File) bool {
+	if pgrp, ok := terminalProcessGroup(file); ok {
+		return pgrp == syscall.Getpgrp()
+	}
+
+	return false
+}
+
+// make the process group of gospace the foreground process group
+// of the terminal again.
+func reclaimTerminal(file *os.File) {
+	var pgrp int32 = int32(syscall.Getpgrp())
+
+	// a background process group is stopped when changing the terminal
+	signal.Ignore(syscall.SIGTTOU)
+	defer signal.Reset(syscall.SIGTTOU)
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
+		file.Fd(),
+		uintptr(syscall.TIOCSPGRP),
+		uintptr(unsafe.Pointer(&pgrp))); 0 != errno {
+		LOG_SHELL.D("unable to reclaim the terminal:", errno)
+	}
+}
+
+// check if the file may be executed by the gospace process
+func executable(path string) error {
+	// X_OK
+	return syscall.Access(path, 0x1)
+}
@@ -0,0 +1,192 @@
+package gospace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// environment variable to select the format of the default logger
+	LOG_FORMAT_ENV = "GOSPACE_LOG_FORMAT"
+	// environment variable to prefix each message with a timestamp
+	LOG_TIMESTAMPS_ENV = "GOSPACE_LOG_TIMESTAMPS"
+	// environment variable to disable colored output, see no-color.org
+	NO_COLOR_ENV = "NO_COLOR"
+)
+
+const (
+	// one human readable line per message
+	LOG_FORMAT_TEXT = "text"
+	// one JSON object per line
+	LOG_FORMAT_JSON = "json"
+)
+
+var (
+	// the destination of the T/D/I/W/E/F messages. programs embedding
+	// the package may replace it, a nil logger discards all messages.
+	LOGGER Logger = DefaultLogger()
+)
+
+// destination of log messages
+type Logger interface {
+	// write the message. the level has already been checked against
+	// the verbosity of the category by the caller.
+	Log(level LogLevel, category LogCategory, message string)
+}
+
+// logger writing each message as a line to a stream
+type StreamLogger struct {
+	// the destination of the messages
+	Output io.Writer
+	// LOG_FORMAT_TEXT or LOG_FORMAT_JSON
+	Format string
+	// prefix text messages with the level name
+	Levels bool
+	// prefix text messages with the time of the message
+	Timestamps bool
+	// highlight text messages with ANSI escape sequences
+	Color bool
+	mutex sync.Mutex
+}
+
+// a JSON-lines entry of the StreamLogger
+type logEntry struct {
+	Time     string      `json:"time"`
+	Level    string      `json:"level"`
+	Category LogCategory `json:"category,omitempty"`
+	Message  string      `json:"message"`
+}
+
+// write the message in the configured format. write errors are
+// ignored, there is nobody left to tell about them.
+func (l *StreamLogger) Log(level LogLevel, category LogCategory, message string) {
+	var line string
+
+	now := time.Now()
+
+	if LOG_FORMAT_JSON == l.Format {
+		data, _ := json.Marshal(&logEntry{now.Format(time.RFC3339Nano), level.String(), category, message})
+		line = string(data)
+	} else {
+		line = l.text(level, category, message, now)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	io.WriteString(l.Output, line+"\n")
+}
+
+// enable the level prefixes
+func (l *StreamLogger) WithLevels() (self *StreamLogger) {
+	self = l
+	l.Levels = true
+
+	return
+}
+
+// enable the timestamp prefixes
+func (l *StreamLogger) WithTimestamps() (self *StreamLogger) {
+	self = l
+	l.Timestamps = true
+
+	return
+}
+
+// enable the highlighting of the levels
+func (l *StreamLogger) WithColor() (self *StreamLogger) {
+	self = l
+	l.Color = true
+
+	return
+}
+
+// write JSON objects instead of text lines
+func (l *StreamLogger) AsJSON() (self *StreamLogger) {
+	self = l
+	l.Format = LOG_FORMAT_JSON
+
+	return
+}
+
+func (l *StreamLogger) String() string {
+	return fmt.Sprintf("StreamLogger(%s)", l.Format)
+}
+
+func (l *StreamLogger) text(level LogLevel, category LogCategory, message string, now time.Time) string {
+	prefix := ""
+
+	if l.Timestamps {
+		prefix += now.Format("15:04:05.000") + " "
+	}
+
+	if l.Levels {
+		name := fmt.Sprintf("%-5s", level)
+
+		if l.Color {
+			name = levelColor(level) + name + "\x1b[0m"
+		}
+
+		prefix += name + " "
+	}
+
+	if LOG_GENERAL != category {
+		prefix += string(category) + ": "
+	}
+
+	return prefix + message
+}
+
+// create a text logger without any decoration
+func NewStreamLogger(out io.Writer) *StreamLogger {
+	return &StreamLogger{Output: out, Format: LOG_FORMAT_TEXT}
+}
+
+// the logger used unless the program replaces LOGGER: level prefixes
+// on stderr, highlighted if stderr is a terminal. the format and
+// timestamps are controlled by GOSPACE_LOG_FORMAT and
+// GOSPACE_LOG_TIMESTAMPS.
+func DefaultLogger() *StreamLogger {
+	logger := NewStreamLogger(os.Stderr).WithLevels()
+
+	if LOG_FORMAT_JSON == os.Getenv(LOG_FORMAT_ENV) {
+		logger.AsJSON()
+	}
+
+	if isEnabled(os.Getenv(LOG_TIMESTAMPS_ENV)) {
+		logger.WithTimestamps()
+	}
+
+	if 0 == len(os.Getenv(NO_COLOR_ENV)) && IsTerminal(os.Stderr) {
+		logger.WithColor()
+	}
+
+	return logger
+}
+
+func levelColor(level LogLevel) string {
+	switch level {
+	case LOG_FATAL, LOG_ERROR:
+		return "\x1b[31m"
+	case LOG_WARN:
+		return "\x1b[33m"
+	case LOG_INFO:
+		return "\x1b[34m"
+	default:
+		return "\x1b[90m"
+	}
+}
+
+func isEnabled(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
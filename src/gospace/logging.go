@@ -29,14 +29,37 @@ const (
 	LOG_TRACE = iota
 )
 
+const (
+	// messages without a subsystem
+	LOG_GENERAL LogCategory = ""
+	// commandline parsing
+	LOG_PARSER LogCategory = "parser"
+	// workspace directory lookups
+	LOG_RESOLVE LogCategory = "resolve"
+	// shell lookup and process handling
+	LOG_SHELL LogCategory = "shell"
+	// workspace and environment composition
+	LOG_WORKSPACE LogCategory = "workspace"
+	// configuration files
+	LOG_CONFIG LogCategory = "config"
+	// go installation discovery
+	LOG_SDK LogCategory = "sdk"
+)
+
 var (
 	// the current verbosity level
 	LOG_LEVEL LogLevel = LOG_OFF
+	// verbosity levels of individual subsystems. a message is logged
+	// if either LOG_LEVEL or the level of its category permits it.
+	LOG_CATEGORIES = map[LogCategory]LogLevel{}
 )
 
 // verbosity level
 type LogLevel int
 
+// subsystem of a log message
+type LogCategory string
+
 func (l LogLevel) String() string {
 	switch l {
 	case LOG_TRACE:
@@ -62,18 +85,96 @@ func (l *LogLevel) Increase(diff int) {
 	*l = ParseLogLevel(int(*l) + diff)
 }
 
+// check if a message of the level is logged for the category
+func (c LogCategory) Enabled(level LogLevel) bool {
+	return level <= LOG_LEVEL || level <= LOG_CATEGORIES[c]
+}
+
+// log a message of the category with verbosity TRACE
+func (c LogCategory) T(message ...interface{}) {
+	c.logAt(LOG_TRACE, message)
+}
+
+// log a message of the category with verbosity DEBUG
+func (c LogCategory) D(message ...interface{}) {
+	c.logAt(LOG_DEBUG, message)
+}
+
+// log a message of the category with verbosity INFO
+func (c LogCategory) I(message ...interface{}) {
+	c.logAt(LOG_INFO, message)
+}
+
+// log a message of the category with verbosity WARN
+func (c LogCategory) W(message ...interface{}) {
+	c.logAt(LOG_WARN, message)
+}
+
+// log a message of the category with verbosity ERROR
+func (c LogCategory) E(message ...interface{}) {
+	c.logAt(LOG_ERROR, message)
+}
+
+// log a message of the category with verbosity FATAL
+func (c LogCategory) F(message ...interface{}) {
+	c.logAt(LOG_FATAL, message)
+}
+
+// format the message like fmt.Println and pass it on to LOGGER
+func (c LogCategory) logAt(level LogLevel, message []interface{}) {
+	if c.Enabled(level) && nil != LOGGER {
+		LOGGER.Log(level, c, strings.TrimSuffix(fmt.Sprintln(message...), "\n"))
+	}
+}
+
 func init() {
 	env := os.Getenv(LOGGING_ENV)
 
 	if 0 < len(env) {
-		if level, err := strconv.Atoi(env); nil == err {
-			LOG_LEVEL = ParseLogLevel(level)
+		SetVerbosity(env)
+
+		D("verbosity set to", LOG_LEVEL, LOG_CATEGORIES, "via environment")
+	}
+}
+
+// apply the verbosity specification to LOG_LEVEL and LOG_CATEGORIES,
+// see ParseLogCategories
+func SetVerbosity(value string) {
+	LOG_LEVEL, LOG_CATEGORIES = ParseLogCategories(value)
+}
+
+// convert a comma separated list of levels into the global level
+// and the levels of individual categories. entries of the form
+// CATEGORY:LEVEL apply to the category only, e.g.
+// "resolve:trace,shell:debug,info". the last global entry wins, the
+// global level defaults to LOG_OFF.
+func ParseLogCategories(value string) (LogLevel, map[LogCategory]LogLevel) {
+	global := LOG_OFF
+	categories := map[LogCategory]LogLevel{}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+
+		if 0 == len(entry) {
+			continue
+		} else if separator := strings.Index(entry, ":"); 0 <= separator {
+			category := LogCategory(strings.ToLower(entry[:separator]))
+			categories[category] = ParseVerbosity(entry[separator+1:])
 		} else {
-			LOG_LEVEL = ParseLogName(env)
+			global = ParseVerbosity(entry)
 		}
+	}
 
-		D("verbosity set to", LOG_LEVEL, "via environment")
+	return global, categories
+}
+
+// convert either a numeric value or a level name into a log level
+func ParseVerbosity(value string) LogLevel {
+	if level, err := strconv.Atoi(value); nil == err {
+		return ParseLogLevel(level)
 	}
+
+	return ParseLogName(value)
 }
 
 // convert the numeric value into a log level instance.
@@ -129,50 +230,38 @@ func ParseLogName(value string) LogLevel {
 	}
 }
 
-// log a message with verbosity TRACE on stdout.
+// log a message with verbosity TRACE via LOGGER.
 // if the current log level is lower, nothing is written.
 func T(message ...interface{}) {
-	if LOG_TRACE <= LOG_LEVEL {
-		fmt.Println(message...)
-	}
+	LOG_GENERAL.logAt(LOG_TRACE, message)
 }
 
-// log a message with verbosity DEBUG on stdout.
+// log a message with verbosity DEBUG via LOGGER.
 // if the current log level is lower, nothing is written.
 func D(message ...interface{}) {
-	if LOG_DEBUG <= LOG_LEVEL {
-		fmt.Println(message...)
-	}
+	LOG_GENERAL.logAt(LOG_DEBUG, message)
 }
 
-// log a message with verbosity INFO on stdout.
+// log a message with verbosity INFO via LOGGER.
 // if the current log level is lower, nothing is written.
 func I(message ...interface{}) {
-	if LOG_INFO <= LOG_LEVEL {
-		fmt.Println(message...)
-	}
+	LOG_GENERAL.logAt(LOG_INFO, message)
 }
 
-// log a message with verbosity WARN on stdout.
+// log a message with verbosity WARN via LOGGER.
 // if the current log level is lower, nothing is written.
 func W(message ...interface{}) {
-	if LOG_WARN <= LOG_LEVEL {
-		fmt.Println(message...)
-	}
+	LOG_GENERAL.logAt(LOG_WARN, message)
 }
 
-// log a message with verbosity ERROR on stdout.
+// log a message with verbosity ERROR via LOGGER.
 // if the current log level is lower, nothing is written.
 func E(message ...interface{}) {
-	if LOG_ERROR <= LOG_LEVEL {
-		fmt.Println(message...)
-	}
+	LOG_GENERAL.logAt(LOG_ERROR, message)
 }
 
-// log a message with verbosity FATAL on stdout.
+// log a message with verbosity FATAL via LOGGER.
 // if the current log level is lower, nothing is written.
 func F(message ...interface{}) {
-	if LOG_FATAL <= LOG_LEVEL {
-		fmt.Println(message...)
-	}
+	LOG_GENERAL.logAt(LOG_FATAL, message)
 }
@@ -0,0 +1,64 @@
+package gospace
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseLogCategories(t *testing.T) {
+	tests := []struct {
+		value      string
+		global     LogLevel
+		categories map[LogCategory]LogLevel
+	}{
+		{"", LOG_OFF, map[LogCategory]LogLevel{}},
+		{"debug", LOG_DEBUG, map[LogCategory]LogLevel{}},
+		{"4", LOG_INFO, map[LogCategory]LogLevel{}},
+		{"WARNING", LOG_WARN, map[LogCategory]LogLevel{}},
+		{"unknown", LOG_OFF, map[LogCategory]LogLevel{}},
+		{"info,trace", LOG_TRACE, map[LogCategory]LogLevel{}},
+		{"resolve:trace", LOG_OFF, map[LogCategory]LogLevel{LOG_RESOLVE: LOG_TRACE}},
+		{
+			"resolve:trace, Shell:debug ,info,",
+			LOG_INFO,
+			map[LogCategory]LogLevel{LOG_RESOLVE: LOG_TRACE, LOG_SHELL: LOG_DEBUG},
+		},
+		{"sdk:5,sdk:error", LOG_OFF, map[LogCategory]LogLevel{LOG_SDK: LOG_ERROR}},
+	}
+
+	for _, test := range tests {
+		global, categories := ParseLogCategories(test.value)
+
+		if global != test.global {
+			t.Errorf("global level of %q = %s, want %s", test.value, global, test.global)
+		}
+
+		if fmt.Sprint(categories) != fmt.Sprint(test.categories) {
+			t.Errorf("category levels of %q = %v, want %v", test.value, categories, test.categories)
+		}
+	}
+}
+
+func TestLogCategoryEnabled(t *testing.T) {
+	defer SetVerbosity("")
+
+	SetVerbosity("warn,resolve:trace")
+
+	tests := []struct {
+		category LogCategory
+		level    LogLevel
+		expected bool
+	}{
+		{LOG_GENERAL, LOG_WARN, true},
+		{LOG_GENERAL, LOG_INFO, false},
+		{LOG_RESOLVE, LOG_TRACE, true},
+		{LOG_SHELL, LOG_ERROR, true},
+		{LOG_SHELL, LOG_DEBUG, false},
+	}
+
+	for _, test := range tests {
+		if actual := test.category.Enabled(test.level); actual != test.expected {
+			t.Errorf("%q enabled at %s = %t, want %t", test.category, test.level, actual, test.expected)
+		}
+	}
+}
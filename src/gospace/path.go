@@ -1,6 +1,7 @@
 package gospace
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -20,13 +21,19 @@ func SearchPathEnvironment(env string, rel string) (string, bool) {
 	path := os.Getenv(env)
 	fragments := strings.Split(path, string(os.PathListSeparator))
 
+	LOG_RESOLVE.T("searching for", rel, "in", env)
+
+	return SearchPathList(fragments, rel)
+}
+
+// same as SearchPathEnvironment, but the lookup directories are
+// provided as a slice instead of an environment variable.
+func SearchPathList(fragments []string, rel string) (string, bool) {
 	// early exit for existing absolute path
 	if filepath.IsAbs(rel) && PathExists(rel) {
 		return rel, true
 	}
 
-	T("searching for", rel, "in", env)
-
 	for _, fragment := range fragments {
 		node := filepath.Join(fragment, rel)
 
@@ -59,3 +66,18 @@ func DirExists(path string) bool {
 
 	return false
 }
+
+// write the data to a temporary file next to the file and rename it
+// afterwards, so readers never see a partially written file. missing
+// parent directories are created.
+func replaceFile(file string, data []byte) error {
+	temp := file + ".tmp"
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); nil != err {
+		return permissionError(filepath.Dir(file), err)
+	} else if err = ioutil.WriteFile(temp, data, 0644); nil != err {
+		return permissionError(temp, err)
+	}
+
+	return os.Rename(temp, file)
+}
@@ -0,0 +1,213 @@
+package gospace
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// number of entries shown at once by the picker
+	PICKER_HEIGHT = 10
+	// the text in front of the filter
+	PICKER_PROMPT = "workspace> "
+)
+
+// interactive, filterable selection of a workspace directory. the
+// entries are filtered by typing, the selection is moved with the
+// arrow keys (or ctrl-p/ctrl-n) and confirmed with enter. escape and
+// ctrl-c abort the selection.
+type Picker struct {
+	// the terminal providing the key strokes
+	Input *os.File
+	// the terminal displaying the list
+	Output io.Writer
+	// the selectable directories in order of preference
+	Candidates []string
+}
+
+// a candidate of the picker and how well it matches the filter
+type pickerEntry struct {
+	path  string
+	rank  int
+	index int
+}
+
+// sort interface ordering by rank, then original order
+type entriesByRank []pickerEntry
+
+func (e entriesByRank) Len() int      { return len(e) }
+func (e entriesByRank) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+func (e entriesByRank) Less(i, j int) bool {
+	if e[i].rank == e[j].rank {
+		return e[i].index < e[j].index
+	}
+
+	return e[i].rank < e[j].rank
+}
+
+// create a picker reading from stdin and drawing on stdout
+func NewPicker(candidates []string) *Picker {
+	return &Picker{os.Stdin, os.Stdout, candidates}
+}
+
+// check if the picker can interact with the user, i.e. stdin and
+// stdout are terminals
+func CanPick() bool {
+	return IsTerminal(os.Stdin) && IsTerminal(os.Stdout)
+}
+
+// show the list and wait for the selection. the error is an
+// ErrSelectionCancelled if the user aborted the selection.
+func (p *Picker) Pick() (string, error) {
+	restore, err := rawMode(p.Input)
+
+	if nil != err {
+		return "", err
+	}
+
+	defer restore()
+
+	filter := []rune{}
+	entries := p.filter("")
+	selected := 0
+	input := make([]byte, 64)
+
+	for {
+		p.draw(string(filter), entries, selected)
+
+		count, err := p.Input.Read(input)
+
+		if nil != err {
+			p.clear()
+			return "", err
+		}
+
+		for _, key := range splitKeys(string(input[:count])) {
+			switch key {
+			case "\r", "\n":
+				if 0 < len(entries) {
+					p.clear()
+					return entries[selected].path, nil
+				}
+			case "\x1b", "\x03", "\x04":
+				p.clear()
+				return "", &ErrSelectionCancelled{}
+			case "\x1b[A", "\x1bOA", "\x10":
+				selected--
+			case "\x1b[B", "\x1bOB", "\x0e", "\t":
+				selected++
+			case "\x7f", "\x08":
+				if 0 < len(filter) {
+					filter = filter[:len(filter)-1]
+					selected = 0
+				}
+			case "\x15":
+				filter = []rune{}
+				selected = 0
+			default:
+				if char := []rune(key)[0]; ' ' <= char {
+					filter = append(filter, char)
+					selected = 0
+				}
+			}
+
+			entries = p.filter(string(filter))
+			selected = clamp(selected, len(entries))
+		}
+	}
+}
+
+// the candidates matching the filter, best match first. the last
+// path component is ranked via MatchRank, the remaining components
+// only count as a substring match of the lowest rank.
+func (p *Picker) filter(filter string) []pickerEntry {
+	entries := []pickerEntry{}
+
+	for i, path := range p.Candidates {
+		rank := MATCH_EXACT
+
+		if 0 < len(filter) {
+			rank = MatchRank(filter, filepath.Base(path))
+		}
+
+		if MATCH_NONE == rank && strings.Contains(strings.ToLower(path), strings.ToLower(filter)) {
+			rank = MATCH_SUBSEQUENCE
+		}
+
+		if MATCH_NONE != rank {
+			entries = append(entries, pickerEntry{path, rank, i})
+		}
+	}
+
+	sort.Sort(entriesByRank(entries))
+
+	return entries
+}
+
+// draw the prompt and the visible part of the list below the cursor.
+// the terminal is in raw mode, hence the explicit carriage returns.
+func (p *Picker) draw(filter string, entries []pickerEntry, selected int) {
+	first := 0
+
+	if selected >= PICKER_HEIGHT {
+		first = selected - PICKER_HEIGHT + 1
+	}
+
+	lines := []string{PICKER_PROMPT + filter}
+
+	for i := first; i < len(entries) && i < first+PICKER_HEIGHT; i++ {
+		if i == selected {
+			lines = append(lines, "\x1b[7m> "+entries[i].path+"\x1b[0m")
+		} else {
+			lines = append(lines, "  "+entries[i].path)
+		}
+	}
+
+	lines = append(lines, fmt.Sprintf("  %d/%d", len(entries), len(p.Candidates)))
+
+	fmt.Fprintf(p.Output, "\r\x1b[J%s", strings.Join(lines, "\r\n"))
+	fmt.Fprintf(p.Output, "\x1b[%dA\r\x1b[%dC", len(lines)-1, len([]rune(PICKER_PROMPT+filter)))
+}
+
+// remove the list from the terminal
+func (p *Picker) clear() {
+	fmt.Fprintf(p.Output, "\r\x1b[J")
+}
+
+// split the input into key strokes: escape sequences of the cursor
+// keys, single characters and control characters
+func splitKeys(input string) []string {
+	keys := []string{}
+	runes := []rune(input)
+
+	for 0 < len(runes) {
+		length := 1
+
+		if '\x1b' == runes[0] && 2 < len(runes) && ('[' == runes[1] || 'O' == runes[1]) {
+			length = 3
+		}
+
+		keys = append(keys, string(runes[:length]))
+		runes = runes[length:]
+	}
+
+	return keys
+}
+
+// limit the index to the range of the entries
+func clamp(index int, count int) int {
+	if index >= count {
+		index = count - 1
+	}
+
+	if 0 > index {
+		index = 0
+	}
+
+	return index
+}
@@ -0,0 +1,74 @@
+package gospace
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// description of a shell launch without spawning the shell
+type Plan struct {
+	// absolute shell binary
+	Shell string `json:"shell"`
+	// complete argument vector including the binary
+	Argv []string `json:"argv"`
+	// working directory of the shell
+	Dir string `json:"dir"`
+	// differences between the current and the shell environment
+	Env []*EnvChange `json:"env"`
+	// origin of each workspace path
+	Paths []*Resolution `json:"paths"`
+}
+
+// write the plan in a human readable form
+func (p *Plan) WriteText(out io.Writer) error {
+	lines := []string{
+		"shell:       " + p.Shell,
+		"argv:        " + strings.Join(p.Argv, " "),
+		"directory:   " + p.Dir,
+		"paths:",
+	}
+
+	for _, path := range p.Paths {
+		lines = append(lines, "  "+path.String())
+	}
+
+	lines = append(lines, "environment:")
+
+	for _, change := range p.Env {
+		lines = append(lines, "  "+change.String())
+	}
+
+	for _, line := range lines {
+		if _, err := io.WriteString(out, line+"\n"); nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// write the plan as indented JSON object
+func (p *Plan) WriteJSON(out io.Writer) error {
+	if data, err := json.MarshalIndent(p, "", "  "); nil != err {
+		return err
+	} else if _, err = out.Write(append(data, '\n')); nil != err {
+		return err
+	}
+
+	return nil
+}
+
+// create the launch plan of the shell in the workspace. the
+// environment differences are computed against _base_, the paths
+// describe how each workspace directory was resolved.
+func NewPlan(shell *Shell, workspace *Workspace, base *Environment, paths []*Resolution) *Plan {
+	argv := append([]string{shell.Path}, shell.Args...)
+	changes := workspace.Environment(base).Diff(base)
+
+	if nil == paths {
+		paths = []*Resolution{}
+	}
+
+	return &Plan{shell.Path, argv, workspace.Root, changes, paths}
+}
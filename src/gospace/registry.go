@@ -0,0 +1,171 @@
+package gospace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	// name of the file containing the named workspaces
+	REGISTRY_NAME string = "workspaces"
+	// resolution source of the named workspaces
+	SOURCE_REGISTRY = "registry"
+)
+
+// a workspace root registered under a name, along with the settings
+// which would otherwise be passed on the commandline
+type NamedWorkspace struct {
+	Name string `json:"name"`
+	// the absolute workspace root
+	Path string `json:"path"`
+	// additional absolute GOPATH directories
+	Include []string `json:"include,omitempty"`
+	// go installation directory, name or version
+	Go string `json:"go,omitempty"`
+	// shell binary
+	Shell string `json:"shell,omitempty"`
+}
+
+// the named workspaces, stored as JSON array in the user configuration
+// directory
+type WorkspaceRegistry struct {
+	// the registry file, empty if the registry is not persisted
+	File       string
+	Workspaces []*NamedWorkspace
+}
+
+// sort interface ordering by name
+type workspacesByName []*NamedWorkspace
+
+func (w workspacesByName) Len() int           { return len(w) }
+func (w workspacesByName) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
+func (w workspacesByName) Less(i, j int) bool { return w[i].Name < w[j].Name }
+
+// the settings of the workspace as configuration layer
+func (w *NamedWorkspace) Config() *Config {
+	config := NewConfig()
+	config.Include = w.Include
+	config.Go = w.Go
+	config.Shell = w.Shell
+
+	return config
+}
+
+func (w *NamedWorkspace) String() string {
+	return fmt.Sprintf("%s -> %s", w.Name, w.Path)
+}
+
+// register the workspace. names have to be unique.
+func (r *WorkspaceRegistry) Add(ws *NamedWorkspace) error {
+	if existing := r.Find(ws.Name); nil != existing {
+		return fmt.Errorf("Workspace '%s' is already registered for '%s'", ws.Name, existing.Path)
+	}
+
+	r.Workspaces = append(r.Workspaces, ws)
+	sort.Sort(workspacesByName(r.Workspaces))
+
+	return nil
+}
+
+// unregister the workspace. the error is an ErrUnknownWorkspace if
+// there is no workspace with the name.
+func (r *WorkspaceRegistry) Remove(name string) error {
+	for i, ws := range r.Workspaces {
+		if ws.Name == name {
+			r.Workspaces = append(r.Workspaces[:i], r.Workspaces[i+1:]...)
+			return nil
+		}
+	}
+
+	return &ErrUnknownWorkspace{name, Suggest(name, r.Names())}
+}
+
+// the workspace registered under the name or nil
+func (r *WorkspaceRegistry) Find(name string) *NamedWorkspace {
+	for _, ws := range r.Workspaces {
+		if ws.Name == name {
+			return ws
+		}
+	}
+
+	return nil
+}
+
+// the first workspace registered for the absolute root or nil
+func (r *WorkspaceRegistry) FindPath(root string) *NamedWorkspace {
+	for _, ws := range r.Workspaces {
+		if sameDirectory(ws.Path, root) {
+			return ws
+		}
+	}
+
+	return nil
+}
+
+// the registered names in alphabetical order
+func (r *WorkspaceRegistry) Names() []string {
+	names := []string{}
+
+	for _, ws := range r.Workspaces {
+		names = append(names, ws.Name)
+	}
+
+	return names
+}
+
+// write the registry to its file. the directory is created if
+// necessary and the file is replaced atomically.
+func (r *WorkspaceRegistry) Save() error {
+	if 0 == len(r.File) {
+		return nil
+	}
+
+	if data, err := json.MarshalIndent(r.Workspaces, "", "  "); nil != err {
+		return err
+	} else {
+		return replaceFile(r.File, append(data, '\n'))
+	}
+}
+
+// read the registry file. a missing file yields an empty registry.
+// the error is an ErrPermission or an ErrInvalidConfig.
+func LoadWorkspaceRegistry(file string) (*WorkspaceRegistry, error) {
+	registry := &WorkspaceRegistry{file, []*NamedWorkspace{}}
+
+	if 0 == len(file) || false == PathExists(file) {
+		return registry, nil
+	}
+
+	LOG_CONFIG.T("reading workspace registry", file)
+
+	if data, err := ioutil.ReadFile(file); nil != err && os.IsPermission(err) {
+		return nil, &ErrPermission{file, err}
+	} else if nil != err {
+		return nil, &ErrInvalidConfig{file, err}
+	} else if err = json.Unmarshal(data, &registry.Workspaces); nil != err {
+		return nil, &ErrInvalidConfig{file, err}
+	}
+
+	sort.Sort(workspacesByName(registry.Workspaces))
+
+	return registry, nil
+}
+
+// the registry of the user, see RegistryFile
+func UserWorkspaceRegistry() (*WorkspaceRegistry, error) {
+	return LoadWorkspaceRegistry(RegistryFile())
+}
+
+// the registry file within the user configuration directory. an empty
+// string is returned if neither XDG_CONFIG_HOME nor HOME are defined.
+func RegistryFile() string {
+	if root := UserConfigDir(); 0 < len(root) {
+		return filepath.Join(root, REGISTRY_NAME)
+	}
+
+	return ""
+}
@@ -0,0 +1,129 @@
+package gospace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkspaceRegistryAdd(t *testing.T) {
+	registry := &WorkspaceRegistry{"", []*NamedWorkspace{}}
+
+	tests := []struct {
+		ws       *NamedWorkspace
+		valid    bool
+		expected string
+	}{
+		{&NamedWorkspace{Name: "web", Path: "/src/web"}, true, "web"},
+		{&NamedWorkspace{Name: "api", Path: "/src/api"}, true, "api web"},
+		{&NamedWorkspace{Name: "cli", Path: "/src/web"}, true, "api cli web"},
+		{&NamedWorkspace{Name: "web", Path: "/src/other"}, false, "api cli web"},
+	}
+
+	for _, test := range tests {
+		err := registry.Add(test.ws)
+
+		if test.valid && nil != err {
+			t.Errorf("adding %s failed: %s", test.ws, err)
+		} else if false == test.valid && nil == err {
+			t.Errorf("adding %s should fail", test.ws)
+		}
+
+		if actual := strings.Join(registry.Names(), " "); actual != test.expected {
+			t.Errorf("names after adding %s = %s, want %s", test.ws, actual, test.expected)
+		}
+	}
+
+	if ws := registry.Find("web"); nil == ws || "/src/web" != ws.Path {
+		t.Errorf("expected web to keep its path, got %v", ws)
+	}
+
+	if ws := registry.FindPath("/src/web"); nil == ws || "cli" != ws.Name {
+		t.Errorf("expected cli to be the first workspace of /src/web, got %v", ws)
+	}
+
+	if ws := registry.Find("unknown"); nil != ws {
+		t.Errorf("expected no workspace, got %s", ws)
+	}
+}
+
+func TestWorkspaceRegistryRemove(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		suggests string
+	}{
+		{"api", "cli web", ""},
+		{"web", "api cli", ""},
+		{"wbe", "api cli web", "web"},
+		{"unknown", "api cli web", ""},
+	}
+
+	for _, test := range tests {
+		registry := &WorkspaceRegistry{"", []*NamedWorkspace{
+			{Name: "api", Path: "/src/api"},
+			{Name: "cli", Path: "/src/cli"},
+			{Name: "web", Path: "/src/web"},
+		}}
+
+		err := registry.Remove(test.name)
+
+		if actual := strings.Join(registry.Names(), " "); actual != test.expected {
+			t.Errorf("names after removing %s = %s, want %s", test.name, actual, test.expected)
+		}
+
+		if test.expected == "api cli web" {
+			if unknown, ok := err.(*ErrUnknownWorkspace); false == ok {
+				t.Errorf("removing %s should yield ErrUnknownWorkspace, got %v", test.name, err)
+			} else if actual := strings.Join(unknown.Suggestions, " "); actual != test.suggests {
+				t.Errorf("suggestions for %s = %s, want %s", test.name, actual, test.suggests)
+			}
+		} else if nil != err {
+			t.Errorf("removing %s failed: %s", test.name, err)
+		}
+	}
+}
+
+func TestWorkspaceRegistrySaveLoad(t *testing.T) {
+	root, err := ioutil.TempDir("", "gospace-registry")
+
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	file := filepath.Join(root, "gospace", REGISTRY_NAME)
+	registry, err := LoadWorkspaceRegistry(file)
+
+	if nil != err || 0 != len(registry.Workspaces) {
+		t.Fatalf("expected an empty registry, got %v (%v)", registry, err)
+	}
+
+	registry.Add(&NamedWorkspace{Name: "web", Path: "/src/web", Go: "1.6"})
+	registry.Add(&NamedWorkspace{Name: "api", Path: "/src/api", Include: []string{"/lib"}})
+
+	if err = registry.Save(); nil != err {
+		t.Fatal(err)
+	}
+
+	if loaded, err := LoadWorkspaceRegistry(file); nil != err {
+		t.Fatal(err)
+	} else if actual := strings.Join(loaded.Names(), " "); "api web" != actual {
+		t.Errorf("loaded names = %s", actual)
+	} else if ws := loaded.Find("web"); "1.6" != ws.Go || "1.6" != ws.Config().Go {
+		t.Errorf("expected web to keep its go version, got %+v", ws)
+	}
+
+	if err = ioutil.WriteFile(file, []byte("{"), 0644); nil != err {
+		t.Fatal(err)
+	}
+
+	if _, err = LoadWorkspaceRegistry(file); nil == err {
+		t.Errorf("expected an invalid registry to fail")
+	} else if _, ok := err.(*ErrInvalidConfig); false == ok {
+		t.Errorf("expected ErrInvalidConfig, got %T", err)
+	}
+}
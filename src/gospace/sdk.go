@@ -0,0 +1,401 @@
+package gospace
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// file in the root of a go installation containing its version
+	SDK_VERSION_FILE string = "VERSION"
+	// environment variable pointing to the active go installation
+	GOROOT_ENV string = "GOROOT"
+	// placeholder for installations without version information
+	SDK_VERSION_UNKNOWN string = "unknown"
+)
+
+var (
+	// glob patterns of common installation directories. a leading ~
+	// is replaced with the home directory.
+	SDK_SEARCH = []string{
+		"/usr/local/go",
+		"/usr/local/go*",
+		"/usr/lib/go",
+		"/usr/lib/go-*",
+		"/usr/lib/golang",
+		"/opt/go*",
+		"~/sdk/go*",
+		"~/go_appengine",
+	}
+)
+
+// a go installation
+type SDK struct {
+	// lookup name; either the configured alias or the directory name
+	Name string
+	// version string as reported by the installation, e.g. go1.5.3
+	Version string
+	// installation directory (GOROOT)
+	Root string
+}
+
+// collection of discovered go installations
+type SDKRegistry struct {
+	sdks []*SDK
+}
+
+// the location of the _go_ binary
+func (s *SDK) Binary() string {
+	return filepath.Join(s.Root, BIN_DIR, "go")
+}
+
+// check if the installation contains a _go_ binary
+func (s *SDK) IsValid() bool {
+	return PathExists(s.Binary())
+}
+
+// the numeric version components. unknown or unparsable versions
+// are returned as empty slice.
+func (s *SDK) Release() []int {
+	return parseRelease(s.Version)
+}
+
+func (s *SDK) String() string {
+	return fmt.Sprintf("SDK(%s, %s, %s)", s.Name, s.Version, s.Root)
+}
+
+// add an installation to the registry. installations with the same
+// root directory are only registered once; a configured name takes
+// precedence over the directory name.
+func (r *SDKRegistry) Add(sdk *SDK, named bool) {
+	for _, known := range r.sdks {
+		if sameDirectory(known.Root, sdk.Root) {
+			if named {
+				known.Name = sdk.Name
+			}
+
+			return
+		}
+	}
+
+	LOG_SDK.T("registering", sdk)
+
+	r.sdks = append(r.sdks, sdk)
+}
+
+// all registered installations ordered by name
+func (r *SDKRegistry) List() []*SDK {
+	list := make([]*SDK, len(r.sdks))
+
+	copy(list, r.sdks)
+	sort.Sort(sdksByName(list))
+
+	return list
+}
+
+// find an installation by directory, name, version or version
+// constraint. an existing directory is used as is, even if it is not
+// part of the registry. a constraint (e.g. _>= 1.5, < 1.7_) selects
+// the newest satisfying installation. otherwise the name has to match
+// exactly, or the query is treated as version prefix (e.g. _1.5_ or
+// _go1.5_) in which case the newest matching installation is returned.
+func (r *SDKRegistry) Find(query string) (*SDK, error) {
+	if DirExists(query) {
+		LOG_SDK.T("go installation", query, "is a directory")
+		return InspectSDK(query, ""), nil
+	} else if IsVersionConstraint(query) {
+		if constraint, err := ParseVersionConstraint(query); nil != err {
+			return nil, err
+		} else {
+			return r.Select(constraint)
+		}
+	}
+
+	for _, sdk := range r.sdks {
+		if query == sdk.Name {
+			LOG_SDK.T("go installation", query, "matches by name")
+			return sdk, nil
+		}
+	}
+
+	if release := parseRelease(query); 0 < len(release) {
+		var best *SDK = nil
+
+		for _, sdk := range r.sdks {
+			if hasReleasePrefix(sdk.Release(), release) &&
+				(nil == best || 0 < compareRelease(sdk.Release(), best.Release())) {
+				best = sdk
+			}
+		}
+
+		if nil != best {
+			LOG_SDK.T("go installation", query, "matches version", best.Version)
+			return best, nil
+		}
+	}
+
+	return nil, &ErrSDKNotFound{query, r.Summary()}
+}
+
+// find the newest installation satisfying the constraint
+func (r *SDKRegistry) Select(constraint *VersionConstraint) (*SDK, error) {
+	var best *SDK = nil
+
+	for _, sdk := range r.sdks {
+		if constraint.Matches(sdk.Release()) &&
+			(nil == best || 0 < compareRelease(sdk.Release(), best.Release())) {
+			best = sdk
+		}
+	}
+
+	if nil == best {
+		return nil, &ErrConstraintMismatch{constraint, nil, r.Summary()}
+	}
+
+	LOG_SDK.T("go installation", best, "satisfies", constraint)
+
+	return best, nil
+}
+
+// comma separated list of the installation names and versions
+func (r *SDKRegistry) Summary() string {
+	if 0 == len(r.sdks) {
+		return "none"
+	}
+
+	names := []string{}
+
+	for _, sdk := range r.List() {
+		names = append(names, sdk.Name+" "+sdk.Version)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// read the version of the installation in _root_. if _name_ is
+// empty, the directory name is used instead.
+func InspectSDK(root string, name string) *SDK {
+	if abs, err := filepath.Abs(root); nil == err {
+		root = abs
+	}
+
+	if 0 == len(name) {
+		name = filepath.Base(root)
+	}
+
+	sdk := &SDK{name, SDK_VERSION_UNKNOWN, root}
+
+	if version, ok := readVersionFile(root); ok {
+		sdk.Version = version
+	} else if version, ok := queryVersion(sdk.Binary()); ok {
+		sdk.Version = version
+	} else {
+		LOG_SDK.D("unable to determine the version of", root)
+	}
+
+	return sdk
+}
+
+// search the common installation directories, GOHOME, GOROOT and
+// the configured locations for go installations. named entries of
+// the configuration are registered with their alias.
+func DiscoverSDKs(config *Config) *SDKRegistry {
+	registry := &SDKRegistry{[]*SDK{}}
+	patterns := append([]string{}, SDK_SEARCH...)
+
+	if nil == config {
+		config = NewConfig()
+	}
+
+	patterns = append(patterns, config.SDKPaths...)
+
+	for _, env := range []string{SDK_ENV, GOROOT_ENV} {
+		if dir := os.Getenv(env); 0 < len(dir) {
+			patterns = append(patterns, dir)
+		}
+	}
+
+	names := make([]string, 0, len(config.SDKs))
+
+	for name := range config.SDKs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if sdk := InspectSDK(expandHome(config.SDKs[name]), name); sdk.IsValid() {
+			registry.Add(sdk, true)
+		} else {
+			LOG_SDK.W("configured go installation", name, "has no go binary")
+		}
+	}
+
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(expandHome(pattern))
+
+		for _, match := range matches {
+			if false == DirExists(match) {
+				continue
+			} else if sdk := InspectSDK(match, ""); sdk.IsValid() {
+				registry.Add(sdk, false)
+			}
+		}
+	}
+
+	LOG_SDK.D("discovered", len(registry.sdks), "go installations")
+
+	return registry
+}
+
+// resolve the installation matching _query_. discovery is skipped
+// if the query is an existing directory.
+func FindSDK(query string, config *Config) (*SDK, error) {
+	if DirExists(query) {
+		return InspectSDK(query, ""), nil
+	}
+
+	return DiscoverSDKs(config).Find(query)
+}
+
+type sdksByName []*SDK
+
+func (s sdksByName) Len() int           { return len(s) }
+func (s sdksByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s sdksByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+func readVersionFile(root string) (string, bool) {
+	file, err := os.Open(filepath.Join(root, SDK_VERSION_FILE))
+
+	if nil != err {
+		return "", false
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	if scanner.Scan() {
+		if version := strings.TrimSpace(scanner.Text()); 0 < len(version) {
+			return version, true
+		}
+	}
+
+	return "", false
+}
+
+// parse the output of _go version_, e.g.
+// go version go1.5.3 linux/amd64
+func queryVersion(binary string) (string, bool) {
+	if false == PathExists(binary) {
+		return "", false
+	}
+
+	output, err := exec.Command(binary, "version").Output()
+
+	if nil != err {
+		LOG_SDK.D("go version failed for", binary, err)
+		return "", false
+	}
+
+	fields := strings.Fields(string(output))
+
+	if 3 <= len(fields) && "version" == fields[1] {
+		return fields[2], true
+	}
+
+	return "", false
+}
+
+// convert a version string like go1.5.3, 1.5 or go1.9rc1 into its
+// numeric components. pre-release suffixes are ignored.
+func parseRelease(version string) []int {
+	release := []int{}
+	version = strings.TrimPrefix(version, "go")
+
+	for _, part := range strings.Split(version, ".") {
+		digits := strings.IndexFunc(part, func(r rune) bool {
+			return '0' > r || '9' < r
+		})
+
+		if 0 == digits {
+			break
+		} else if 0 < digits {
+			part = part[:digits]
+		}
+
+		if number, err := strconv.Atoi(part); nil == err {
+			release = append(release, number)
+		} else {
+			break
+		}
+
+		if 0 < digits {
+			break
+		}
+	}
+
+	return release
+}
+
+// compare two releases component-wise. missing components are
+// treated as zero.
+func compareRelease(a []int, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+
+		if i < len(a) {
+			x = a[i]
+		}
+
+		if i < len(b) {
+			y = b[i]
+		}
+
+		if x != y {
+			return x - y
+		}
+	}
+
+	return 0
+}
+
+func hasReleasePrefix(release []int, prefix []int) bool {
+	if len(release) < len(prefix) {
+		return false
+	}
+
+	for i, number := range prefix {
+		if release[i] != number {
+			return false
+		}
+	}
+
+	return true
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~") {
+		return os.Getenv(HOME_ENV) + path[1:]
+	}
+
+	return path
+}
+
+func sameDirectory(a string, b string) bool {
+	if x, err := filepath.EvalSymlinks(a); nil == err {
+		a = x
+	}
+
+	if y, err := filepath.EvalSymlinks(b); nil == err {
+		b = y
+	}
+
+	return a == b
+}
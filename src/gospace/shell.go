@@ -1,11 +1,11 @@
 package gospace
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"syscall"
 )
 
 var (
@@ -15,8 +15,6 @@ var (
 	SHELL_ENV string = "SHELL"
 	// environment variable to read for binary lookups
 	PATH_ENV string = "PATH"
-	// shell resolver error
-	resolveError error = errors.New("Unable to find any suitable shell")
 )
 
 // command + arguments
@@ -27,29 +25,59 @@ type Shell struct {
 
 type builder struct {
 	artifact string
+	// the paths which have been looked up
+	candidates []string
+	// the first candidate which exists, but is not executable
+	denied error
 }
 
 // execute the shell. the shell will be invoked with the internal
 // commandline arguments. the environment is enhanced with various
 // go related variables. stdin, stdout and stderr are attached to
-// the sub-process.
-//
+// the sub-process. if _simulate_ is set, nothing is executed (see
+// NewPlan to describe the launch instead).
+// gospace waits for the shell to exit and forwards the signals
+// SIGINT, SIGTERM, SIGHUP and SIGWINCH to its process group. the exit
+// status of the shell is available via ExitStatus.
 func (s *Shell) Launch(workspace *Workspace, simulate bool) error {
 	var shell *exec.Cmd = exec.Command(s.Path, s.Args...)
 
-	if err := os.Setenv(PATH_ENV, workspace.GeneratePATH()); nil != err {
-		return err
+	if simulate {
+		LOG_SHELL.I("simulating", s, "in", workspace)
+		return nil
 	}
 
 	shell.Dir = workspace.Root
 	shell.Stdin = os.Stdin
 	shell.Stdout = os.Stdout
 	shell.Stderr = os.Stderr
-	shell.Env = append(os.Environ(),
-		workspace.EnvGOPATH(),
-		workspace.EnvGOBIN())
+	shell.Env = workspace.Environment(CurrentEnvironment()).Environ()
 
-	return shell.Run()
+	if err := supervise(shell); nil != err {
+		return permissionError(s.Path, err)
+	}
+
+	return nil
+}
+
+// replace the gospace process with the shell. the environment and
+// working directory are the same as with Launch. the function only
+// returns if the shell could not be executed. see CanReplace for
+// platform support.
+func (s *Shell) Exec(workspace *Workspace) error {
+	argv := append([]string{s.Path}, s.Args...)
+
+	LOG_SHELL.D("replacing gospace with", s)
+
+	env := workspace.Environment(CurrentEnvironment())
+
+	return permissionError(s.Path, replace(s.Path, argv, env.Environ(), workspace.Root))
+}
+
+// check if the shell will be used interactively, i.e. stdin and
+// stdout are attached to a terminal.
+func (s *Shell) IsInteractive() bool {
+	return IsTerminal(os.Stdin) && IsTerminal(os.Stdout)
 }
 
 func (s *Shell) String() string {
@@ -62,6 +90,24 @@ func (s *Shell) String() string {
 	return fmt.Sprintf("Shell(%s%s)", s.Path, argv)
 }
 
+// extract the exit code of the shell from the error returned by
+// Launch. a shell killed by signal N yields 128+N, like in POSIX
+// shells. the second value is false if the error does not originate
+// from the shell process itself.
+func ExitStatus(err error) (int, bool) {
+	if exit, ok := err.(*exec.ExitError); ok {
+		if status, ok := exit.Sys().(syscall.WaitStatus); ok {
+			if status.Signaled() {
+				return 128 + int(status.Signal()), true
+			}
+
+			return status.ExitStatus(), true
+		}
+	}
+
+	return 0, false
+}
+
 func (b *builder) hasArtifact() bool {
 	return 0 < len(b.artifact)
 }
@@ -69,44 +115,56 @@ func (b *builder) hasArtifact() bool {
 func (b *builder) use(path string) (self *builder) {
 	self = b
 
-	T("attempting to resolve shell", path)
+	LOG_SHELL.T("attempting to resolve shell", path)
 
 	if b.hasArtifact() {
-		D("shell already found; skipping lookup")
+		LOG_SHELL.D("shell already found; skipping lookup")
 		return
 	} else if 0 == len(path) {
-		D("no shell path provided for lookup")
-		return
-	} else if abs, ok := SearchPathEnvironment(PATH_ENV, path); false == ok {
-		D("shell lookup via PATH failed")
+		LOG_SHELL.D("no shell path provided for lookup")
 		return
+	}
+
+	b.candidates = append(b.candidates, path)
+
+	if abs, ok := SearchPathEnvironment(PATH_ENV, path); false == ok {
+		LOG_SHELL.D("shell lookup via PATH failed")
 	} else if DirExists(abs) {
-		I("shell path is a directory")
-		return
+		LOG_SHELL.I("shell path is a directory")
+	} else if err := executable(abs); nil != err {
+		LOG_SHELL.I("shell", abs, "is not executable")
+
+		if nil == b.denied {
+			b.denied = &ErrPermission{abs, err}
+		}
 	} else {
-		// TODO: check permission bits for executable flag
 		b.artifact = abs
-
-		return
 	}
+
+	return
 }
 
+// the resolved shell binary. shells which exist but are not
+// executable are reported as ErrPermission rather than
+// ErrShellNotFound.
 func (b *builder) build() (string, error) {
 	if b.hasArtifact() {
 		return b.artifact, nil
+	} else if nil != b.denied {
+		return "", b.denied
 	}
 
-	return "", resolveError
+	return "", &ErrShellNotFound{b.candidates}
 }
 
 // resolve the path against various sources. the first match is used.
 // if the path is empty, the shell specified in the environment as
 // _SHELL_, otherwise the fallback value **/bin/sh** is used.
 // if the path is not absolute it is resolved against the PATH
-// directories. if all lookups yield no usable result, an error
-// is returned.
+// directories. if all lookups yield no usable result, an
+// ErrShellNotFound or ErrPermission is returned.
 func ResolveShell(path string, args []string) (*Shell, error) {
-	builder := builder{""}
+	builder := builder{"", []string{}, nil}
 	osshell := os.Getenv(SHELL_ENV)
 
 	builder.
@@ -115,7 +173,7 @@ func ResolveShell(path string, args []string) (*Shell, error) {
 		use(SHELL_DEFAULT)
 
 	if binary, err := builder.build(); nil == err {
-		D("using shell", binary)
+		LOG_SHELL.D("using shell", binary)
 
 		return &Shell{binary, args}, nil
 	} else {
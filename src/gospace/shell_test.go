@@ -0,0 +1,35 @@
+package gospace
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestExitStatus(t *testing.T) {
+	tests := []struct {
+		script   string
+		status   int
+		external bool
+	}{
+		{"exit 0", 0, false},
+		{"exit 3", 3, true},
+		{"exit 65", 65, true},
+		{"kill -TERM $$", 128 + 15, true},
+		{"kill -KILL $$", 128 + 9, true},
+	}
+
+	for _, test := range tests {
+		err := exec.Command("/bin/sh", "-c", test.script).Run()
+
+		if status, ok := ExitStatus(err); ok != test.external {
+			t.Errorf("%q yields %v, expected an exit status: %t", test.script, err, test.external)
+		} else if status != test.status {
+			t.Errorf("exit status of %q = %d, want %d", test.script, status, test.status)
+		}
+	}
+
+	if status, ok := ExitStatus(errors.New("Shell not found")); ok || 0 != status {
+		t.Errorf("expected no exit status of an unrelated error, got %d", status)
+	}
+}
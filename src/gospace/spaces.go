@@ -2,7 +2,25 @@ package gospace
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// resolution source of paths relative to the working directory
+	SOURCE_PWD = "PWD"
+	// resolution source of the configured lookup directories
+	SOURCE_CONFIG = "config"
+	// resolution source of the implicit workspace root
+	SOURCE_DEFAULT = "default"
+	// resolution source of interactively selected directories
+	SOURCE_PICKER = "picker"
+	// environment variable overriding the resolution order
+	RESOLVE_ORDER_ENV = "GOSPACE_RESOLVE_ORDER"
 )
 
 var (
@@ -11,26 +29,372 @@ var (
 	SPACES_ENV = "GOSPACES"
 	// environment variable containing lookup directories
 	CDPATH_ENV = "CDPATH"
+	// configured lookup directories, searched last by default
+	SPACES_DEFAULT = []string{}
+	// the resolution sources in order of precedence. sources missing
+	// from the list are not searched at all.
+	RESOLVE_ORDER = []string{SOURCE_REGISTRY, SOURCE_PWD, SPACES_ENV, CDPATH_ENV, SOURCE_CONFIG, SOURCE_HISTORY}
+	// every known resolution source
+	RESOLVE_SOURCES = []string{SOURCE_REGISTRY, SOURCE_PWD, SPACES_ENV, CDPATH_ENV, SOURCE_CONFIG, SOURCE_HISTORY}
 )
 
-// resolve the directory against each entry of CDPATH and GOSPACES.
-// if the value is already an absolute path and exists in the
-// filesystem, it is returned without any further lookups.
+// outcome of a workspace path lookup
+type Resolution struct {
+	// the value as provided by the user
+	Input string `json:"input"`
+	// the absolute directory
+	Path string `json:"path"`
+	// the lookup which yielded the directory
+	Source string `json:"source"`
+	// every location which was checked, in order of precedence
+	Candidates []*Candidate `json:"candidates,omitempty"`
+}
+
+// a location checked while resolving a workspace path
+type Candidate struct {
+	// the absolute path
+	Path string `json:"path"`
+	// the resolution source providing the lookup directory
+	Source string `json:"source"`
+	// whether the path refers to an existing directory
+	Exists bool `json:"exists"`
+}
+
+func (r *Resolution) String() string {
+	return fmt.Sprintf("%s -> %s (%s)", r.Input, r.Path, r.Source)
+}
+
+func (c *Candidate) String() string {
+	return fmt.Sprintf("%s (%s)", c.Path, c.Source)
+}
+
+// resolve the directory against the named workspaces, the lookup
+// directories and the history in the order of RESOLVE_ORDER, falling
+// back to partial matches of the name. if the value is already an
+// absolute path and exists in the filesystem, it is returned without
+// any further lookups. the name "-" refers to the most recently
+// launched workspace.
 func ResolveGospace(dir string) (string, error) {
-	if abs, err := filepath.Abs(dir); nil == err {
-		if DirExists(abs) {
-			D("gospace", dir, "resolves to current working directory")
-			return abs, nil
+	if resolution, err := ExplainGospace(dir); nil != err {
+		return "", err
+	} else {
+		return resolution.Path, nil
+	}
+}
+
+// same as ResolveGospace, but the result also describes which lookup
+// yielded the directory and which candidates were checked. if none of
+// the candidates exists, the name is matched partially against the
+// named workspaces, the directories within the lookup directories and
+// the history, e.g. _proj_ resolves to _myproject_ if no other
+// directory matches as well.
+// equally good matches are told apart by their frecency.
+//
+// the error is an ErrWorkspaceNotFound, an ErrAmbiguousWorkspace if
+// several directories match equally well, an ErrPermission if the
+// directory exists but is not accessible or an ErrInvalidConfig if
+// GOSPACE_RESOLVE_ORDER names an unknown source or the registry of
+// named workspaces is invalid.
+func ExplainGospace(dir string) (*Resolution, error) {
+	var resolution *Resolution
+	var denied error
+
+	order, err := ResolveOrder()
+
+	if nil != err {
+		return nil, err
+	}
+
+	registry, err := UserWorkspaceRegistry()
+
+	if nil != err {
+		return nil, err
+	}
+
+	history := UserHistory()
+
+	if PREVIOUS_GOSPACE == dir {
+		return previousGospace(history)
+	}
+
+	candidates := gospaceCandidates(dir, order, registry, history)
+
+	for _, candidate := range candidates {
+		if false == candidate.Exists {
+			if _, err := os.Stat(candidate.Path); os.IsPermission(err) && nil == denied {
+				denied = &ErrPermission{candidate.Path, err}
+			}
+		} else if nil == resolution {
+			LOG_RESOLVE.D("gospace", dir, "was found in", candidate.Source)
+			resolution = &Resolution{dir, candidate.Path, candidate.Source, candidates}
+		}
+	}
+
+	if nil != resolution {
+		return resolution, nil
+	} else if nil != denied {
+		return nil, denied
+	}
+
+	matches := matchGospaces(dir, order, registry, history)
+
+	if 1 == len(matches) {
+		LOG_RESOLVE.D("gospace", dir, "matches", matches[0].path, "in", matches[0].source)
+
+		candidates = append(candidates, &Candidate{matches[0].path, matches[0].source, true})
+
+		return &Resolution{dir, matches[0].path, matches[0].source, candidates}, nil
+	} else if 1 < len(matches) {
+		paths := []string{}
+
+		for _, match := range matches {
+			paths = append(paths, match.path)
+			candidates = append(candidates, &Candidate{match.path, match.source, true})
+		}
+
+		return nil, &ErrAmbiguousWorkspace{dir, paths, candidates}
+	}
+
+	return nil, &ErrWorkspaceNotFound{dir, candidates, suggestGospaces(dir)}
+}
+
+// the most recently launched workspace
+func previousGospace(history *History) (*Resolution, error) {
+	last := history.Last()
+
+	if nil == last {
+		return nil, &ErrWorkspaceNotFound{PREVIOUS_GOSPACE, []*Candidate{}, []string{}}
+	}
+
+	candidates := []*Candidate{{last.Path, SOURCE_HISTORY, DirExists(last.Path)}}
+
+	if false == candidates[0].Exists {
+		return nil, &ErrWorkspaceNotFound{PREVIOUS_GOSPACE, candidates, []string{}}
+	}
+
+	LOG_RESOLVE.D("previous gospace is", last.Path)
+
+	return &Resolution{PREVIOUS_GOSPACE, last.Path, SOURCE_HISTORY, candidates}, nil
+}
+
+// the effective resolution order: GOSPACE_RESOLVE_ORDER if defined,
+// otherwise RESOLVE_ORDER
+func ResolveOrder() ([]string, error) {
+	value := os.Getenv(RESOLVE_ORDER_ENV)
+
+	if 0 == len(value) {
+		return RESOLVE_ORDER, nil
+	} else if order, err := ParseResolveOrder(strings.Split(value, ",")); nil != err {
+		return nil, &ErrInvalidConfig{RESOLVE_ORDER_ENV, err}
+	} else {
+		return order, nil
+	}
+}
+
+// convert the case-insensitive source names, e.g. "gospaces" or "pwd",
+// to resolution sources. duplicates are dropped, unknown names are
+// rejected.
+func ParseResolveOrder(names []string) ([]string, error) {
+	order := []string{}
+	known := map[string]bool{}
+	for _, name := range names {
+		source := ""
+
+		for _, candidate := range RESOLVE_SOURCES {
+			if strings.EqualFold(candidate, strings.TrimSpace(name)) {
+				source = candidate
+			}
+		}
+
+		if 0 == len(source) {
+			return nil, fmt.Errorf("Unknown resolution source '%s' (expected %s)", name, strings.ToLower(strings.Join(RESOLVE_SOURCES, ", ")))
+		} else if false == known[source] {
+			known[source] = true
+			order = append(order, source)
+		}
+	}
+
+	return order, nil
+}
+
+// the lookup directories of the resolution source
+func lookupDirectories(source string) []string {
+	switch source {
+	case SOURCE_PWD:
+		return []string{""}
+	case SPACES_ENV, CDPATH_ENV:
+		return filepath.SplitList(os.Getenv(source))
+	case SOURCE_CONFIG:
+		return SPACES_DEFAULT
+	default:
+		return []string{}
+	}
+}
+
+// the absolute paths the directory may refer to, in the given order
+// of resolution sources. absolute directories are only checked as is,
+// regardless of the order.
+// the registry provides the workspace named like the directory, the
+// history the launched workspaces ending with the directory. each path
+// is reported once, by the first source providing it.
+func gospaceCandidates(dir string, order []string, registry *WorkspaceRegistry, history *History) []*Candidate {
+	candidates := []*Candidate{}
+	known := map[string]bool{}
+
+	if filepath.IsAbs(dir) {
+		abs := filepath.Clean(dir)
+		return append(candidates, &Candidate{abs, SOURCE_PWD, DirExists(abs)})
+	}
+
+	for _, source := range order {
+		LOG_RESOLVE.T("searching for", dir, "in", source)
+
+		if SOURCE_REGISTRY == source {
+			if ws := registry.Find(dir); nil != ws && false == known[ws.Path] {
+				known[ws.Path] = true
+				candidates = append(candidates, &Candidate{ws.Path, source, DirExists(ws.Path)})
+			}
+		}
+
+		if SOURCE_HISTORY == source {
+			for _, entry := range history.Lookup(dir, time.Now()) {
+				if false == known[entry.Path] {
+					known[entry.Path] = true
+					candidates = append(candidates, &Candidate{entry.Path, source, DirExists(entry.Path)})
+				}
+			}
+		}
+
+		for _, root := range lookupDirectories(source) {
+			abs, err := filepath.Abs(filepath.Join(root, dir))
+
+			if nil != err || known[abs] {
+				continue
+			}
+
+			known[abs] = true
+			candidates = append(candidates, &Candidate{abs, source, DirExists(abs)})
+		}
+	}
+
+	return candidates
+}
+
+// the named workspaces and the names of the directories which can be
+// resolved via the lookup directories of GOSPACES, CDPATH and the
+// configuration. each name is reported once, even if it exists in
+// several lookup directories.
+func ListGospaces() []string {
+	names := []string{}
+	known := map[string]bool{}
+
+	if registry, err := UserWorkspaceRegistry(); nil != err {
+		LOG_RESOLVE.W("ignoring workspace registry", err)
+	} else {
+		for _, name := range registry.Names() {
+			known[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, candidate := range gospaceDirectories([]string{SPACES_ENV, CDPATH_ENV, SOURCE_CONFIG}) {
+		if name := filepath.Base(candidate.Path); false == known[name] {
+			known[name] = true
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// the launched workspaces in descending order of frecency, followed by
+// the named workspaces and the absolute directories within the lookup
+// directories in the order of resolution, e.g. to offer them for an
+// interactive selection. the working directory itself is not listed.
+func PickGospaces() ([]string, error) {
+	paths := []string{}
+	known := map[string]bool{}
+	order, err := ResolveOrder()
+
+	if nil != err {
+		return nil, err
+	}
+
+	for _, entry := range UserHistory().Ranked(time.Now()) {
+		if DirExists(entry.Path) {
+			known[entry.Path] = true
+			paths = append(paths, entry.Path)
+		}
+	}
+
+	if registry, err := UserWorkspaceRegistry(); nil != err {
+		return nil, err
+	} else {
+		for _, ws := range registry.Workspaces {
+			if false == known[ws.Path] && DirExists(ws.Path) {
+				known[ws.Path] = true
+				paths = append(paths, ws.Path)
+			}
+		}
+	}
+
+	for _, candidate := range gospaceDirectories(order) {
+		if false == known[candidate.Path] {
+			paths = append(paths, candidate.Path)
+		}
+	}
+
+	return paths, nil
+}
+
+// the non-hidden directories within the lookup directories of the
+// resolution sources. each directory is listed once.
+func gospaceDirectories(order []string) []*Candidate {
+	candidates := []*Candidate{}
+	known := map[string]bool{}
+
+	for _, source := range order {
+		if SOURCE_PWD == source {
+			continue
+		}
+
+		for _, root := range lookupDirectories(source) {
+			if 0 == len(root) {
+				continue
+			}
+
+			entries, err := ioutil.ReadDir(root)
+
+			if nil != err {
+				LOG_RESOLVE.T("unable to list lookup directory", root, err)
+				continue
+			}
+
+			for _, entry := range entries {
+				name := entry.Name()
+				abs, err := filepath.Abs(filepath.Join(root, name))
+
+				if nil != err || known[abs] || strings.HasPrefix(name, ".") || false == DirExists(abs) {
+					continue
+				}
+
+				known[abs] = true
+				candidates = append(candidates, &Candidate{abs, source, true})
+			}
 		}
 	}
 
-	if abs, ok := SearchPathEnvironment(CDPATH_ENV, dir); ok {
-		D("gospace", dir, "was found in", CDPATH_ENV)
-		return abs, nil
-	} else if abs, ok := SearchPathEnvironment(SPACES_ENV, dir); ok {
-		D("gospace", dir, "was found in", SHELL_ENV)
-		return abs, nil
+	return candidates
+}
+
+// the workspace names resembling the unresolvable directory. paths
+// with several components are not guessed.
+func suggestGospaces(dir string) []string {
+	if strings.ContainsRune(filepath.Clean(dir), filepath.Separator) {
+		return []string{}
 	}
 
-	return "", fmt.Errorf("No such directory '%s'", dir)
+	return Suggest(dir, ListGospaces())
 }
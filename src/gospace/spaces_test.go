@@ -0,0 +1,85 @@
+package gospace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExplainGospaceReducedOrder(t *testing.T) {
+	root := lookupFixture(t, "myproject", "elsewhere/absolute")
+	defer os.RemoveAll(root)
+	defer os.Unsetenv(SPACES_ENV)
+
+	os.Setenv(RESOLVE_ORDER_ENV, "gospaces,cdpath")
+	os.Setenv(XDG_CONFIG_ENV, filepath.Join(root, "config"))
+	os.Setenv(XDG_STATE_ENV, filepath.Join(root, "state"))
+	defer os.Unsetenv(RESOLVE_ORDER_ENV)
+	defer os.Unsetenv(XDG_CONFIG_ENV)
+	defer os.Unsetenv(XDG_STATE_ENV)
+
+	absolute := filepath.Join(root, "elsewhere", "absolute")
+
+	tests := []struct {
+		dir      string
+		path     string
+		source   string
+		notFound bool
+	}{
+		{absolute, absolute, SOURCE_PWD, false},
+		{absolute + "/", absolute, SOURCE_PWD, false},
+		{filepath.Join(root, "missing"), "", "", true},
+		{"myproject", filepath.Join(root, "myproject"), SPACES_ENV, false},
+		{"elsewhere/absolute", filepath.Join(root, "elsewhere", "absolute"), SPACES_ENV, false},
+	}
+
+	for _, test := range tests {
+		resolution, err := ExplainGospace(test.dir)
+
+		if _, ok := err.(*ErrWorkspaceNotFound); test.notFound {
+			if false == ok {
+				t.Errorf("ExplainGospace(%q) = %v, %v, want ErrWorkspaceNotFound", test.dir, resolution, err)
+			}
+		} else if nil != err {
+			t.Errorf("ExplainGospace(%q) failed: %s", test.dir, err)
+		} else if resolution.Path != test.path || resolution.Source != test.source {
+			t.Errorf("ExplainGospace(%q) = %s, want %s (%s)", test.dir, resolution, test.path, test.source)
+		}
+	}
+}
+
+func TestExplainGospaceAmbiguous(t *testing.T) {
+	root := lookupFixture(t, "github.com", "gitlab.com")
+	defer os.RemoveAll(root)
+	defer os.Unsetenv(SPACES_ENV)
+
+	os.Setenv(RESOLVE_ORDER_ENV, "gospaces")
+	os.Setenv(XDG_CONFIG_ENV, filepath.Join(root, "config"))
+	os.Setenv(XDG_STATE_ENV, filepath.Join(root, "state"))
+	defer os.Unsetenv(RESOLVE_ORDER_ENV)
+	defer os.Unsetenv(XDG_CONFIG_ENV)
+	defer os.Unsetenv(XDG_STATE_ENV)
+
+	_, err := ExplainGospace("git")
+	ambiguous, ok := err.(*ErrAmbiguousWorkspace)
+
+	if false == ok {
+		t.Fatalf("expected an ErrAmbiguousWorkspace, got %v", err)
+	}
+
+	expected := []*Candidate{
+		{filepath.Join(root, "git"), SPACES_ENV, false},
+		{filepath.Join(root, "github.com"), SPACES_ENV, true},
+		{filepath.Join(root, "gitlab.com"), SPACES_ENV, true},
+	}
+
+	if len(ambiguous.Searched) != len(expected) {
+		t.Fatalf("expected the candidates %v, got %v", expected, ambiguous.Searched)
+	}
+
+	for i, candidate := range ambiguous.Searched {
+		if *candidate != *expected[i] {
+			t.Errorf("candidate %d is %v, want %v", i, candidate, expected[i])
+		}
+	}
+}
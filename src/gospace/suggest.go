@@ -0,0 +1,144 @@
+package gospace
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// maximum number of suggestions for a mistyped value
+	SUGGESTION_LIMIT = 3
+)
+
+// candidate of a mistyped value
+type suggestion struct {
+	name     string
+	distance int
+}
+
+// sort interface ordering by distance, then name
+type suggestionsByDistance []suggestion
+
+func (s suggestionsByDistance) Len() int      { return len(s) }
+func (s suggestionsByDistance) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s suggestionsByDistance) Less(i, j int) bool {
+	if s[i].distance == s[j].distance {
+		return s[i].name < s[j].name
+	}
+
+	return s[i].distance < s[j].distance
+}
+
+// the number of single character insertions, deletions, substitutions
+// and transpositions of adjacent characters required to turn _a_ into
+// _b_ (optimal string alignment distance)
+func EditDistance(a string, b string) int {
+	source := []rune(a)
+	target := []rune(b)
+	distances := make([][]int, len(source)+1)
+
+	for i := range distances {
+		distances[i] = make([]int, len(target)+1)
+		distances[i][0] = i
+	}
+
+	for j := range distances[0] {
+		distances[0][j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			distances[i][j] = minimum(
+				distances[i-1][j]+1,
+				distances[i][j-1]+1,
+				distances[i-1][j-1]+cost)
+
+			if 1 < i && 1 < j && source[i-1] == target[j-2] && source[i-2] == target[j-1] {
+				distances[i][j] = minimum(distances[i][j], distances[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return distances[len(source)][len(target)]
+}
+
+// the candidates which are most likely meant by the mistyped value,
+// closest first. a candidate qualifies if it starts with the value or
+// is within an edit distance of a third of the value length (at
+// least 1). at most SUGGESTION_LIMIT candidates are returned.
+func Suggest(value string, candidates []string) []string {
+	suggestions := []suggestion{}
+	threshold := len(value) / 3
+
+	if 1 > threshold {
+		threshold = 1
+	}
+
+	for _, candidate := range candidates {
+		if candidate == value {
+			continue
+		}
+
+		distance := EditDistance(strings.ToLower(value), strings.ToLower(candidate))
+
+		if 0 < len(value) && strings.HasPrefix(candidate, value) {
+			distance = 0
+		}
+
+		if distance <= threshold {
+			suggestions = append(suggestions, suggestion{candidate, distance})
+		}
+	}
+
+	sort.Sort(suggestionsByDistance(suggestions))
+
+	names := []string{}
+
+	for i := 0; i < len(suggestions) && i < SUGGESTION_LIMIT; i++ {
+		names = append(names, suggestions[i].name)
+	}
+
+	return names
+}
+
+// the suggestions as an addition to an error message, e.g.
+// " (did you mean 'a' or 'b'?)". the result is empty if there are no
+// suggestions.
+func DidYouMean(suggestions []string) string {
+	if 0 == len(suggestions) {
+		return ""
+	}
+
+	quoted := []string{}
+
+	for _, suggestion := range suggestions {
+		quoted = append(quoted, "'"+suggestion+"'")
+	}
+
+	if 1 == len(quoted) {
+		return " (did you mean " + quoted[0] + "?)"
+	}
+
+	last := len(quoted) - 1
+
+	return " (did you mean " + strings.Join(quoted[:last], ", ") + " or " + quoted[last] + "?)"
+}
+
+func minimum(values ...int) int {
+	result := values[0]
+
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+
+	return result
+}
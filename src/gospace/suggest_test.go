@@ -0,0 +1,78 @@
+package gospace
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"launch", "launch", 0},
+		{"lanch", "launch", 1},
+		{"launchh", "launch", 1},
+		{"launxh", "launch", 1},
+		{"luanch", "launch", 1},
+		{"kitten", "sitting", 3},
+		{"ca", "abc", 3},
+		{"größe", "grösse", 2},
+	}
+
+	for _, test := range tests {
+		if actual := EditDistance(test.a, test.b); actual != test.expected {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", test.a, test.b, actual, test.expected)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	commands := []string{"add", "list", "launch", "remove", "which", "help"}
+
+	tests := []struct {
+		value      string
+		candidates []string
+		expected   []string
+	}{
+		{"lanch", commands, []string{"launch"}},
+		{"lst", commands, []string{"list"}},
+		{"l", commands, []string{"launch", "list"}},
+		{"LIST", commands, []string{"list"}},
+		{"list", commands, []string{}},
+		{"xyz", commands, []string{}},
+		{"", commands, []string{}},
+		{"a", []string{"ab", "ac", "ad", "ae", "b"}, []string{"ab", "ac", "ad"}},
+		{"proj", []string{"proj-tools", "prof", "grpc"}, []string{"proj-tools", "prof"}},
+	}
+
+	for _, test := range tests {
+		actual := Suggest(test.value, test.candidates)
+
+		if strings.Join(actual, " ") != strings.Join(test.expected, " ") {
+			t.Errorf("Suggest(%q) = %v, want %v", test.value, actual, test.expected)
+		}
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	tests := []struct {
+		suggestions []string
+		expected    string
+	}{
+		{[]string{}, ""},
+		{[]string{"list"}, " (did you mean 'list'?)"},
+		{[]string{"list", "launch"}, " (did you mean 'list' or 'launch'?)"},
+		{[]string{"ab", "ac", "ad"}, " (did you mean 'ab', 'ac' or 'ad'?)"},
+	}
+
+	for _, test := range tests {
+		if actual := DidYouMean(test.suggestions); actual != test.expected {
+			t.Errorf("DidYouMean(%v) = %q, want %q", test.suggestions, actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package gospace
+
+import (
+	"errors"
+	"os"
+)
+
+// check if the file refers to a character device
+func IsTerminal(file *os.File) bool {
+	if info, err := file.Stat(); nil == err {
+		return 0 != info.Mode()&os.ModeCharDevice
+	}
+
+	return false
+}
+
+// check if gospace can replace itself with another process
+func CanReplace() bool {
+	return false
+}
+
+func replace(binary string, argv []string, env []string, dir string) error {
+	return errors.New("Replacing the gospace process is not supported")
+}
+
+func rawMode(file *os.File) (func(), error) {
+	return nil, errors.New("Interactive selection is not supported on this platform")
+}
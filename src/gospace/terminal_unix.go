@@ -0,0 +1,76 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package gospace
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// check if the file refers to the controlling terminal
+func IsTerminal(file *os.File) bool {
+	_, ok := terminalProcessGroup(file)
+
+	return ok
+}
+
+// check if gospace can replace itself with another process
+func CanReplace() bool {
+	return true
+}
+
+// replace the gospace process with the binary. the working directory
+// is changed before, since execve does not support it. the function
+// only returns in case of an error.
+func replace(binary string, argv []string, env []string, dir string) error {
+	if err := os.Chdir(dir); nil != err {
+		return err
+	}
+
+	return syscall.Exec(binary, argv, env)
+}
+
+// switch the terminal to raw input without echo. the returned function
+// restores the previous settings. stty is used instead of the termios
+// ioctls, since their request codes differ between the platforms.
+func rawMode(file *os.File) (func(), error) {
+	state, err := stty(file, "-g")
+
+	if nil != err {
+		return nil, err
+	} else if _, err = stty(file, "raw", "-echo"); nil != err {
+		return nil, err
+	}
+
+	return func() {
+		if _, err := stty(file, state); nil != err {
+			LOG_GENERAL.W("unable to restore the terminal settings", err)
+		}
+	}, nil
+}
+
+func stty(file *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = file
+
+	output, err := cmd.Output()
+
+	return strings.TrimSpace(string(output)), err
+}
+
+func terminalProcessGroup(file *os.File) (int, bool) {
+	var pgrp int32
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
+		file.Fd(),
+		uintptr(syscall.TIOCGPGRP),
+		uintptr(unsafe.Pointer(&pgrp))); 0 != errno {
+		return 0, false
+	}
+
+	return int(pgrp), true
+}
@@ -1,8 +1,11 @@
 package gospace
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path"
+	"sort"
 	"strings"
 )
 
@@ -24,7 +27,9 @@ var (
 	WS_DEFAULT string
 )
 
-// GO workspace paths
+// GO workspace paths. the paths only contain the directories of the
+// workspace itself, the inherited values are added by Environment
+// and the Generate* helpers.
 type Workspace struct {
 	// working directory
 	Root string
@@ -32,9 +37,15 @@ type Workspace struct {
 	GoPath []string
 	// OS PATH directories
 	OsPath []string
+	// additional environment variables
+	Env map[string]string
+	// GO installation directory; empty if the inherited one is used
+	GoRoot string
+	// keep the inherited GOPATH directories after the workspace ones
+	KeepGoPath bool
 }
 
-// generate the GOPATH environment pair
+// generate the GOPATH environment pair, see GenerateGOPATH
 func (w *Workspace) EnvGOPATH() string {
 	return WS_ENV + "=" + w.GenerateGOPATH()
 }
@@ -44,9 +55,13 @@ func (w *Workspace) EnvGOBIN() string {
 	return PKG_ENV + "=" + w.GenerateGOBIN()
 }
 
-// generate the GOPATH value
+// generate the GOPATH value of the workspace launched from the
+// current process, i.e. the value Environment yields for
+// CurrentEnvironment. it includes the inherited GOPATH if KeepGoPath
+// is set.
 func (w *Workspace) GenerateGOPATH() string {
-	return concatPath(w.GoPath, w.Root)
+	value, _ := w.Environment(CurrentEnvironment()).Get(WS_ENV)
+	return value
 }
 
 // generate the GOBIN value
@@ -54,9 +69,99 @@ func (w *Workspace) GenerateGOBIN() string {
 	return path.Join(w.Root, BIN_DIR)
 }
 
-// generate the OS PATH value
+// generate the complete environment of the workspace. the workspace
+// directories are prepended to PATH and GOPATH of _base_ (GOPATH is
+// replaced unless KeepGoPath is set), GOBIN is replaced and all
+// other variables are kept. without a selected GO installation the
+// inherited GOROOT is kept, unless it does not point to a GO
+// installation anymore. _base_ is not modified.
+func (w *Workspace) Environment(base *Environment) *Environment {
+	env := base.Copy()
+
+	if w.KeepGoPath {
+		env.Prepend(WS_ENV, append([]string{w.Root}, w.GoPath...)...)
+	} else {
+		env.Set(WS_ENV, concatPath(w.GoPath, w.Root))
+	}
+
+	env.Prepend(OS_ENV, append([]string{w.GenerateGOBIN()}, w.OsPath...)...)
+	env.Set(PKG_ENV, w.GenerateGOBIN())
+
+	if 0 < len(w.GoRoot) {
+		env.Set(GOROOT_ENV, w.GoRoot)
+	} else if inherited, ok := env.Get(GOROOT_ENV); ok && false == (&SDK{"", "", inherited}).IsValid() {
+		LOG_WORKSPACE.D("dropping stale", GOROOT_ENV, inherited)
+		env.Unset(GOROOT_ENV)
+	}
+
+	for _, name := range w.envNames() {
+		env.Set(name, w.Env[name])
+	}
+
+	return env
+}
+
+// compare the GOROOT reported by the _go_ binary found in the
+// workspace PATH with the GOROOT of the workspace. an error is
+// returned if they differ. nothing is checked if GOROOT is undefined
+// or no _go_ binary is found.
+func (w *Workspace) VerifyGOROOT() error {
+	var env *Environment = w.Environment(CurrentEnvironment())
+	var expected string
+	var binary string
+	var ok bool
+
+	if expected, ok = env.Get(GOROOT_ENV); false == ok {
+		LOG_WORKSPACE.T("no", GOROOT_ENV, "to verify")
+		return nil
+	} else if binary, ok = SearchPathList(env.pathList(OS_ENV), "go"); false == ok {
+		LOG_WORKSPACE.D("no go binary found in the workspace PATH")
+		return nil
+	}
+
+	// let the binary report its own installation directory
+	env.Unset(GOROOT_ENV)
+
+	query := exec.Command(binary, "env", GOROOT_ENV)
+	query.Env = env.Environ()
+
+	output, err := query.Output()
+
+	if nil != err {
+		return fmt.Errorf("Unable to query %s of %s: %s", GOROOT_ENV, binary, err.Error())
+	}
+
+	if reported := strings.TrimSpace(string(output)); false == sameDirectory(reported, expected) {
+		return fmt.Errorf("%s reports %s %s, but the workspace uses %s",
+			binary,
+			GOROOT_ENV,
+			reported,
+			expected)
+	}
+
+	LOG_WORKSPACE.T(binary, "matches", GOROOT_ENV, expected)
+
+	return nil
+}
+
+// generate the OS PATH value of the workspace launched from the
+// current process, i.e. the workspace directories followed by the
+// inherited PATH. see GenerateGOPATH.
 func (w *Workspace) GeneratePATH() string {
-	return concatPath(w.OsPath, w.GenerateGOBIN())
+	value, _ := w.Environment(CurrentEnvironment()).Get(OS_ENV)
+	return value
+}
+
+func (w *Workspace) envNames() []string {
+	names := make([]string, 0, len(w.Env))
+
+	for name := range w.Env {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
 }
 
 func (w *Workspace) String() string {
@@ -72,50 +177,96 @@ func init() {
 // as well as additional lookup directories for core or external
 // libraries. the values of _paths_ are expected to be absolute
 // directory names.
-// the the SDK directory is not an empty string, it is expected to
-// contain the path to a GO installation which will be included in
-// the OS path (its **bin** subdirectory to be precise).
-// the _keepEnv_ directive will reuse the existing GOPATH directories
-// and prepend the workspace directories.
-func ParseWorkspace(paths []string, sdk string, keepEnv bool) (ws *Workspace, err error) {
+// the the SDK is not an empty string, it is expected to contain the
+// path, name or version of a GO installation (see SDKRegistry.Find)
+// which will be included in the OS path (its **bin** subdirectory to
+// be precise).
+// the _keepEnv_ directive will reuse the GOPATH directories of the
+// environment passed to Workspace.Environment and prepend the
+// workspace directories.
+// the _config_ values are used as another input: its include
+// directories are appended to the GOPATH, its GO installation is
+// used if _sdk_ is empty and its environment variables are exported
+// in the shell. it may be nil. if the configured GO installation is
+// a version constraint, an explicit _sdk_ has to satisfy it.
+func ParseWorkspace(paths []string, sdk string, keepEnv bool, config *Config) (ws *Workspace, err error) {
 	var gopath []string
 	var ospath []string
 	var langdir string
 	var workdir string
+	var goinst *SDK
+	var goroot string
+	var envvars map[string]string
+
+	if nil == config {
+		config = NewConfig()
+	}
 
 	// generate GOPATH
 
 	switch len(paths) {
 	case 0:
-		T("using", WS_DEFAULT, "as the workspace root")
+		LOG_WORKSPACE.T("using", WS_DEFAULT, "as the workspace root")
 		gopath = []string{}
 		workdir = WS_DEFAULT
 	case 1:
-		T("workspace root:", paths[0], "no other includes")
+		LOG_WORKSPACE.T("workspace root:", paths[0], "no other includes")
 		gopath = []string{}
 		workdir = paths[0]
 	default:
-		T("workspace root:", paths[0], "+ includes")
+		LOG_WORKSPACE.T("workspace root:", paths[0], "+ includes")
 		gopath = paths[1:]
 		workdir = paths[0]
 	}
 
-	if keepEnv {
-		D("appending", WS_ENV, "to workspace path")
-		gopath = extendPath(gopath, WS_ENV)
+	if 0 < len(config.Include) {
+		LOG_WORKSPACE.T("appending configured includes", config.Include)
+		gopath = append(gopath, config.Include...)
 	}
 
 	// generate PATH
 
+	if 0 == len(sdk) && 0 < len(config.Go) {
+		LOG_WORKSPACE.D("using configured GO installation")
+		sdk = config.Go
+	}
+
 	if 0 == len(sdk) {
-		ospath = extendPath([]string{}, OS_ENV)
+		ospath = []string{}
+	} else if goinst, err = FindSDK(sdk, config); nil != err {
+		return nil, err
+	} else if err = checkConstraint(goinst, config.Go); nil != err {
+		return nil, err
 	} else {
-		D("using custom GO installation", sdk)
-		langdir = path.Join(sdk, BIN_DIR)
-		ospath = extendPath([]string{langdir}, OS_ENV)
+		LOG_WORKSPACE.D("using custom GO installation", goinst)
+		goroot = goinst.Root
+		langdir = path.Join(goinst.Root, BIN_DIR)
+		ospath = []string{langdir}
 	}
 
-	return &Workspace{workdir, gopath, ospath}, nil
+	// copy the extra variables to avoid sharing the config map
+
+	envvars = make(map[string]string, len(config.Env))
+
+	for name, value := range config.Env {
+		envvars[name] = value
+	}
+
+	return &Workspace{workdir, gopath, ospath, envvars, goroot, keepEnv}, nil
+}
+
+// verify the installation against the configured GO installation,
+// given that it is a version constraint.
+func checkConstraint(sdk *SDK, configured string) error {
+	if false == IsVersionConstraint(configured) {
+		return nil
+	} else if constraint, err := ParseVersionConstraint(configured); nil != err {
+		return err
+	} else if false == constraint.Matches(sdk.Release()) {
+		return &ErrConstraintMismatch{constraint, sdk, ""}
+	}
+
+	return nil
 }
 
 func concatPath(path []string, directory string) string {
@@ -128,10 +279,3 @@ func concatPath(path []string, directory string) string {
 		return directory + sep + postfix
 	}
 }
-
-func extendPath(path []string, environment string) []string {
-	extension := os.Getenv(environment)
-	fragments := strings.Split(extension, string(os.PathListSeparator))
-
-	return append(path, fragments...)
-}
@@ -0,0 +1,60 @@
+package gospace
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWorkspaceGenerate(t *testing.T) {
+	sep := string(os.PathListSeparator)
+
+	defer os.Setenv(WS_ENV, os.Getenv(WS_ENV))
+	defer os.Setenv(OS_ENV, os.Getenv(OS_ENV))
+
+	os.Setenv(WS_ENV, "/inherited")
+	os.Setenv(OS_ENV, "/usr/bin"+sep+"/bin")
+
+	tests := []struct {
+		ws     *Workspace
+		gopath string
+		path   string
+	}{
+		{
+			&Workspace{Root: "/ws", GoPath: []string{}, OsPath: []string{}},
+			"/ws",
+			"/ws/bin:/usr/bin:/bin",
+		},
+		{
+			&Workspace{Root: "/ws", GoPath: []string{"/lib"}, OsPath: []string{"/go/bin"}},
+			"/ws:/lib",
+			"/ws/bin:/go/bin:/usr/bin:/bin",
+		},
+		{
+			&Workspace{Root: "/ws", GoPath: []string{"/lib"}, OsPath: []string{}, KeepGoPath: true},
+			"/ws:/lib:/inherited",
+			"/ws/bin:/usr/bin:/bin",
+		},
+	}
+
+	for _, test := range tests {
+		gopath := strings.Replace(test.gopath, ":", sep, -1)
+		path := strings.Replace(test.path, ":", sep, -1)
+
+		if actual := test.ws.GenerateGOPATH(); actual != gopath {
+			t.Errorf("%s GOPATH = %q, want %q", test.ws, actual, gopath)
+		}
+
+		if actual := test.ws.EnvGOPATH(); actual != WS_ENV+"="+gopath {
+			t.Errorf("%s GOPATH pair = %q, want %q", test.ws, actual, WS_ENV+"="+gopath)
+		}
+
+		if actual := test.ws.GeneratePATH(); actual != path {
+			t.Errorf("%s PATH = %q, want %q", test.ws, actual, path)
+		}
+
+		if actual := test.ws.EnvGOBIN(); actual != PKG_ENV+"=/ws/bin" {
+			t.Errorf("%s GOBIN pair = %q", test.ws, actual)
+		}
+	}
+}